@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +11,7 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/taskvanguard/taskvanguard/internal/analyzer"
+	"github.com/taskvanguard/taskvanguard/internal/goals"
 	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
 	"github.com/taskvanguard/taskvanguard/pkg/theme"
 	"github.com/taskvanguard/taskvanguard/pkg/types"
@@ -48,36 +48,79 @@ func runSmartAdd(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	output, newTaskId, err := env.Client.AddTaskToTaskWarrior(args)
+	// One reader for every prompt, separate readers would each buffer input meant for the next prompt
+	reader := bufio.NewReader(os.Stdin)
+
+	// goal:<name> links the task to the goal with that name, TaskWarrior needs its UUID
+	twArgs, err := resolveGoalAttributes(reader, goals.NewManager(env.Config), args)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		return
+	}
+
+	output, newTaskId, err := env.Client.AddTaskToTaskWarrior(twArgs)
 	fmt.Print(string(output))
 	if err != nil {
 		fmt.Printf("Could not create the original Task you provided: %v\n", err)
 		return
 	}
 
-	s := spinner.New(spinner.CharSets[40], 100*time.Millisecond) 
-	s.Prefix = "Working... "
-	s.Start()
-
-	taskArgs := strings.Join(args, " ")
-	suggestion, err := analyzer.AnalyzeSingleTaskWithLLM(env.Config, taskArgs, env.UserGoals, env.UserProjects)
-	if err != nil {
-		fmt.Printf("Error analyzing task: %v\n", err)
+	// Remember the task as created to detect edits made while waiting for the LLM and the user
+	created, err := env.Client.GetTaskByID(strconv.Itoa(newTaskId))
+	if err != nil || created == nil {
+		fmt.Printf("Could not read back the created task %d: %v\n", newTaskId, err)
 		return
 	}
 
-	s.Stop()
+	taskArgs := strings.Join(args, " ")
+	var suggestion *types.TaskSuggestion
+	var userConfirmations map[string]bool
 
-	if env.Config.Settings.EnableLowercase {
-		lowercaseTaskSuggestion(suggestion)
-	}
+	for {
+		s := spinner.New(spinner.CharSets[40], 100*time.Millisecond) 
+		s.Prefix = "Working... "
+		s.Start()
 
-	displaySuggestions(env.Config, taskArgs, suggestion)
-	userConfirmations := askUserConfirmation(env.Config, suggestion)
+		suggestion, err = analyzer.AnalyzeSingleTaskWithLLM(env.Config, taskArgs, env.UserGoals, env.UserProjects)
+		if err != nil {
+			s.Stop()
+			fmt.Printf("Error analyzing task: %v\n", err)
+			return
+		}
 
-	if !anyAccepted(userConfirmations) {
-		fmt.Println(theme.Success("\nAdded only provided Task without modifications."))
-		return
+		s.Stop()
+
+		if env.Config.Settings.EnableLowercase {
+			lowercaseTaskSuggestion(suggestion)
+		}
+
+		displaySuggestions(env.Config, taskArgs, suggestion)
+		userConfirmations = askUserConfirmation(reader, env.Config, suggestion)
+
+		if !anyAccepted(userConfirmations) {
+			fmt.Println(theme.Success("\nAdded only provided Task without modifications."))
+			return
+		}
+
+		stale, err := env.Client.FindStale([]types.Task{*created})
+		if err != nil {
+			fmt.Printf("Error checking task %d for changes: %v\n", newTaskId, err)
+			return
+		}
+		if len(stale) == 0 {
+			break
+		}
+
+		action := askConflictAction(reader, stale[0].Fetched, stale[0].Current)
+		if action == conflictSkip {
+			fmt.Println(theme.Warn("Suggestions skipped, task left as it is."))
+			return
+		}
+		if action == conflictForce {
+			break
+		}
+		created = &stale[0].Current
+		taskArgs = taskToArgs(*created)
 	}
 
 	if userConfirmations["subtasks"] && len(suggestion.Subtasks) > 0 {
@@ -88,7 +131,7 @@ func runSmartAdd(cmd *cobra.Command, args []string) {
 	}
 
 	enhancedArgs := buildEnhancedTaskArgs(suggestion, userConfirmations)
-	if err := addAnnotationsInTaskWarrior(env.Config, newTaskId, suggestion.AdditionalInfo); err != nil {
+	if err := addAnnotationsInTaskWarrior(env.Client, env.Config, newTaskId, suggestion.AdditionalInfo); err != nil {
 		fmt.Printf("Error adding annotations to task id: %d \nError: %v\n", newTaskId, err)
 		return
 	}
@@ -149,10 +192,9 @@ func displaySuggestions(cfg *types.Config, taskArgs string, suggestion *types.Ta
 	
 }
 
-func askUserConfirmation(cfg *types.Config, suggestion *types.TaskSuggestion) map[string]bool {
+func askUserConfirmation(reader *bufio.Reader, cfg *types.Config, suggestion *types.TaskSuggestion) map[string]bool {
 
 	// 2. Prompt loop
-	reader := bufio.NewReader(os.Stdin)
 	applyAll := false
 	denyAll := false
 
@@ -240,9 +282,18 @@ func buildEnhancedTaskArgs(suggestion *types.TaskSuggestion, userConfirmations m
 	return args
 }
 
-func addAnnotationsInTaskWarrior(cfg *types.Config, taskId int, additionalInfo map[string]string) error {
+func addAnnotationsInTaskWarrior(client *taskwarrior.Client, cfg *types.Config, taskId int, additionalInfo map[string]string) error {
 	taskIdStr := strconv.Itoa(taskId)
 
+	task, err := client.GetTaskByID(taskIdStr)
+	if err != nil {
+		return err
+	}
+	var existing []types.Annotation
+	if task != nil {
+		existing = task.Annotations
+	}
+
 	for key, info := range additionalInfo {
 		if info == "" {
 			continue
@@ -259,7 +310,9 @@ func addAnnotationsInTaskWarrior(cfg *types.Config, taskId int, additionalInfo m
 			}
 		}
 		annotationText := fmt.Sprintf("%s%s: %s", symbol, label, info)
-		if err := addSingleAnnotation(taskIdStr, annotationText); err != nil {
+
+		// Annotations created by TaskVanguard start with their label, update those instead of piling up duplicates
+		if err := client.UpsertAnnotation(taskIdStr, existing, symbol+label+":", annotationText); err != nil {
 			return err
 		}
 	}
@@ -267,17 +320,6 @@ func addAnnotationsInTaskWarrior(cfg *types.Config, taskId int, additionalInfo m
 	return nil
 }
 
-func addSingleAnnotation(taskId string, value string) error {
-	cmd := exec.Command("task", taskId, "annotate", value)
-	
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to add annotation to task %s: %v\nOutput: %s", taskId, err, string(output))
-	}
-	
-	return nil
-}
-
 func anyAccepted(confirmations map[string]bool) bool {
 	for _, v := range confirmations {
 		if v {
@@ -12,6 +12,7 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/taskvanguard/taskvanguard/internal/analyzer"
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
 	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
 	"github.com/taskvanguard/taskvanguard/pkg/theme"
 	"github.com/taskvanguard/taskvanguard/pkg/types"
@@ -19,10 +20,11 @@ import (
 )
 
 var analyzeCmd = &cobra.Command{
-	Use:   "analyze",
+	Use:   "analyze [filter | @preset...]",
 	Short: "Analyze tasks for categorization and priority insights",
 	Long: `Analyze your TaskWarrior tasks to get AI-powered insights about
 categorization, priority adjustments, and potential task relationships.`,
+	ValidArgsFunction: completeFilterPresets,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		s := spinner.New(spinner.CharSets[40], 100*time.Millisecond) 
@@ -36,7 +38,6 @@ categorization, priority adjustments, and potential task relationships.`,
 			return
 		}
 
-		var taskList []types.Task
 		// If analyze is used without arguments, ask user about analyzing all tasks
 		if len(args) == 0 {
 			s.Stop() // Stop spinner before user interaction
@@ -50,20 +51,16 @@ categorization, priority adjustments, and potential task relationships.`,
 			
 			// User accepted, restart spinner and get all tasks
 			s.Start()
-			taskList, err = env.Client.GetPendingTasks()
-			if err != nil {
-				s.Stop()
-				fmt.Println(theme.Error("Failed to get tasks: " + err.Error()))
-				return
-			}
-		} else {
-			// Use arguments as filter
-			taskList, err = env.Client.GetPendingTasksWithArgsFiltered(env.Config, args)
-			if err != nil {
-				s.Stop()
-				fmt.Println(theme.Error("Failed to get filtered tasks: " + err.Error()))
-				return
-			}
+		}
+
+		taskList, blocked, err := fetchAnalyzeTasks(env, args)
+		if err != nil {
+			s.Stop()
+			fmt.Println(theme.Error("Failed to get tasks: " + err.Error()))
+			return
+		}
+		if len(blocked) > 0 {
+			fmt.Println(theme.Unimportant(fmt.Sprintf("\n%d tasks excluded by privacy filters (see 'vanguard privacy audit analyze')", len(blocked))))
 		}
 
 		// Apply task limit
@@ -82,89 +79,117 @@ categorization, priority adjustments, and potential task relationships.`,
 		// Count and display task count
 		fmt.Printf("\n→ Found %d tasks for analysis!\n", len(taskList))
 
-		// Convert tasks to task args format for batch processing
-		taskArgs := make([]string, len(taskList))
-		for i, task := range taskList {
-			var sb strings.Builder
-			sb.WriteString(task.Description)
-			if task.Project != "" {
-				sb.WriteString(" project:")
-				sb.WriteString(task.Project)
-			}
+		s.Stop()
+		if err := analyzeAndApply(env, bufio.NewReader(os.Stdin), taskList); err != nil {
+			fmt.Println(theme.Error(err.Error()))
+		}
+	},
+}
 
-			for _, tag := range task.Tags {
-				sb.WriteString(" +")
-				sb.WriteString(tag)
-			}
+// fetchAnalyzeTasks returns the pending tasks matching args split into those that may be analyzed
+// and those blocked by the privacy filters. Without args all pending tasks in the active context are candidates.
+func fetchAnalyzeTasks(env *taskwarrior.RuntimeContext, args []string) ([]types.Task, []privacy.Blocked, error) {
+	gate, err := privacy.NewGate(env.Config)
+	if err != nil {
+		return nil, nil, err
+	}
 
-			if task.Priority != "" {
-				sb.WriteString(" priority:")
-				sb.WriteString(task.Priority)
-			}
+	filterArgs, err := env.Client.ResolveFilter(env.Config, args)
+	if err != nil {
+		return nil, nil, err
+	}
 
-			taskArgs[i] = sb.String()
-		}
+	tasks, err := env.Client.GetPendingTasksWithArgs(filterArgs)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		// Analyze batch
-		suggestions, err := analyzer.AnalyzeBatchTasksWithLLM(
-			env.Config, 
-			taskArgs, 
-			env.UserGoals, 
-			env.UserProjects,
-		)
-		s.Stop()
-		if err != nil {
-			fmt.Println(theme.Error("Analysis failed: " + err.Error()))
-			return
-		}
+	allowed, blocked := gate.Partition(tasks)
+	return allowed, blocked, nil
+}
 
-		if env.Config.Settings.EnableLowercase {
-			lowercaseTaskBatchSuggestion(suggestions)
-		}
+// analyzeAndApply sends the tasks to the LLM and lets the user apply the suggestions.
+// Tasks that changed in the meantime can be handed back to it for re-analysis.
+func analyzeAndApply(env *taskwarrior.RuntimeContext, reader *bufio.Reader, taskList []types.Task) error {
+	s := spinner.New(spinner.CharSets[40], 100*time.Millisecond) 
+	s.Prefix = "→ Analyzing your task list... "
+	s.Start()
+
+	// Convert tasks to task args format for batch processing
+	taskArgs := make([]string, len(taskList))
+	for i, task := range taskList {
+		taskArgs[i] = taskToArgs(task)
+	}
+
+	// Analyze batch
+	suggestions, err := analyzer.AnalyzeBatchTasksWithLLM(
+		env.Config, 
+		taskArgs, 
+		env.UserGoals, 
+		env.UserProjects,
+	)
+	s.Stop()
+	if err != nil {
+		return fmt.Errorf("Analysis failed: %v", err)
+	}
 
-		// === User Prompt: Edit Mode Selection ===
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Print("How do you want to proceed? [o]ne-by-one / [e]dit all: ")
-		input, _ := reader.ReadString('\n')
-		input = strings.ToLower(strings.TrimSpace(input))
+	if env.Config.Settings.EnableLowercase {
+		lowercaseTaskBatchSuggestion(suggestions)
+	}
 
-		var oneByOneMode, massEditMode bool
-		switch input {
-		case "o", "":
-			oneByOneMode = true
-		case "e":
-			massEditMode = true
-		default:
-			fmt.Println("Invalid mode selected.")
-			return
-		}
+	reanalyze := func(tasks []types.Task) error {
+		return analyzeAndApply(env, reader, tasks)
+	}
 
-		if oneByOneMode {
-			err := oneByOneInteractiveApply(*env.Client, reader, taskList, suggestions)
-			if err != nil {
-				fmt.Println(theme.Error("Failed to apply suggestions: " + err.Error()))
-			}
-			return
-		}
+	// === User Prompt: Edit Mode Selection ===
+	fmt.Print("How do you want to proceed? [o]ne-by-one / [e]dit all: ")
+	input, _ := reader.ReadString('\n')
+	input = strings.ToLower(strings.TrimSpace(input))
 
-		if massEditMode {
-			err := massEditViaEditor(*env.Client, taskList, suggestions)
-			if err != nil {
-				fmt.Println(theme.Error("Mass edit failed: " + err.Error()))
-			}
-			return
+	switch input {
+	case "o", "":
+		if err := oneByOneInteractiveApply(*env.Client, reader, taskList, suggestions, reanalyze); err != nil {
+			return fmt.Errorf("Failed to apply suggestions: %v", err)
+		}
+	case "e":
+		if err := massEditViaEditor(*env.Client, reader, taskList, suggestions, reanalyze); err != nil {
+			return fmt.Errorf("Mass edit failed: %v", err)
 		}
+	default:
+		fmt.Println("Invalid mode selected.")
+	}
 
-	},
+	return nil
 }
 
+// taskToArgs converts a task back into `task add` style arguments
+func taskToArgs(task types.Task) string {
+	var sb strings.Builder
+	sb.WriteString(task.Description)
+	if task.Project != "" {
+		sb.WriteString(" project:")
+		sb.WriteString(task.Project)
+	}
+
+	for _, tag := range task.Tags {
+		sb.WriteString(" +")
+		sb.WriteString(tag)
+	}
+
+	if task.Priority != "" {
+		sb.WriteString(" priority:")
+		sb.WriteString(task.Priority)
+	}
 
+	return sb.String()
+}
 
 func oneByOneInteractiveApply(
 	client taskwarrior.Client,
 	reader *bufio.Reader,
 	taskList []types.Task,
 	suggestions *types.BatchTaskSuggestion,
+	reanalyze func([]types.Task) error,
 ) error {
 	var acceptAll, denyAll bool
 
@@ -222,23 +247,71 @@ func oneByOneInteractiveApply(
 
 	// === Apply Suggestions OneByOne ===
 	fmt.Println(theme.Title("\n=== Applying Accepted Suggestions ==="))
+	var changes []taskwarrior.TaskChange
 	for i, apply := range userChoices {
 		if !apply {
 			continue
 		}
 
-		orig := taskList[i]
-		suggestion := suggestions.TaskAnalyses[i]
-		args := utils.TaskSuggestionToArgs(suggestion)
-		_, err := client.ModifyTaskInTaskWarrior(orig.ID, args)
+		changes = append(changes, taskwarrior.ChangeFromSuggestion(taskList[i], suggestions.TaskAnalyses[i]))
+	}
 
-		if err != nil {
-			fmt.Println(theme.Error(fmt.Sprintf("Task %d: %s", i, err.Error())))
-		} else {
-			fmt.Println(theme.Success(fmt.Sprintf("Task %d applied: %s", i, orig.Description)))
+	return applyTaskChanges(client, reader, taskList, changes, reanalyze)
+}
+
+// applyTaskChanges imports all changes at once. Tasks that were modified since they were
+// fetched are shown to the user, who can re-analyze, force or skip them.
+func applyTaskChanges(
+	client taskwarrior.Client,
+	reader *bufio.Reader,
+	taskList []types.Task,
+	changes []taskwarrior.TaskChange,
+	reanalyze func([]types.Task) error,
+) error {
+	if len(changes) == 0 {
+		fmt.Println(theme.Warn("No modifications to apply"))
+		return nil
+	}
+
+	conflicts, err := client.ModifyTasks(changes)
+	if err != nil {
+		return err
+	}
+	fmt.Println(theme.Success(fmt.Sprintf("%d of %d tasks modified", len(changes)-len(conflicts), len(changes))))
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	fetched := make(map[string]types.Task, len(taskList))
+	for _, task := range taskList {
+		fetched[task.UUID] = task
+	}
+
+	var forced []taskwarrior.TaskChange
+	var outdated []types.Task
+	for _, conflict := range conflicts {
+		switch askConflictAction(reader, fetched[conflict.Change.UUID], conflict.Current) {
+		case conflictForce:
+			conflict.Change.Modified = conflict.Current.Modified
+			forced = append(forced, conflict.Change)
+		case conflictReanalyze:
+			outdated = append(outdated, conflict.Current)
+		default:
+			fmt.Println(theme.Warn(fmt.Sprintf("Skipped task %d", conflict.Current.ID)))
 		}
 	}
 
+	if len(forced) > 0 {
+		if err := applyTaskChanges(client, reader, taskList, forced, reanalyze); err != nil {
+			return err
+		}
+	}
+
+	if len(outdated) > 0 {
+		return reanalyze(outdated)
+	}
+
 	return nil
 }
 
@@ -263,8 +336,10 @@ func lowercaseTaskBatchSuggestion(batch *types.BatchTaskSuggestion) {
 	}
 }
 
-func massEditViaEditor(client taskwarrior.Client, taskList []types.Task, suggestions *types.BatchTaskSuggestion) error {
+func massEditViaEditor(client taskwarrior.Client, reader *bufio.Reader, taskList []types.Task, suggestions *types.BatchTaskSuggestion, reanalyze func([]types.Task) error) error {
 	var commands []string
+	// Lines left as generated are applied from the suggestion itself
+	generated := make(map[string]int)
 	
 	// Generate task modify commands
 	for i, suggestion := range suggestions.TaskAnalyses {
@@ -291,8 +366,12 @@ func massEditViaEditor(client taskwarrior.Client, taskList []types.Task, suggest
 			commands = append(commands, comment.String())
 			
 			cmdParts := []string{"task", "modify", strconv.Itoa(orig.ID)}
-			cmdParts = append(cmdParts, args...)
-			commands = append(commands, strings.Join(cmdParts, " "))
+			for _, arg := range args {
+				cmdParts = append(cmdParts, utils.ShellQuote(arg))
+			}
+			command := strings.Join(cmdParts, " ")
+			generated[command] = i
+			commands = append(commands, command)
 		}
 	}
 	
@@ -309,7 +388,7 @@ func massEditViaEditor(client taskwarrior.Client, taskList []types.Task, suggest
 	defer os.Remove(tempFile.Name())
 	
 	// Write commands to temp file with explanatory header
-	header := "#!/bin/bash\n# - Delete any lines you don't want to execute\n# - Modify the task modify commands as needed\n# - All remaining commands will be executed when you save and exit\n#\n# Format: task modify <task_id> <modifications>\n"
+	header := "#!/bin/bash\n# - Delete any lines you don't want to execute\n# - Modify the task modify commands as needed, quote descriptions like in a shell\n# - All remaining commands will be executed when you save and exit\n#\n# Format: task modify <task_id> <modifications>\n"
 	content := header + strings.Join(commands, "\n") + "\n"
 	if _, err := tempFile.WriteString(content); err != nil {
 		tempFile.Close()
@@ -341,19 +420,33 @@ func massEditViaEditor(client taskwarrior.Client, taskList []types.Task, suggest
 	}
 	
 	modifiedCommands := strings.Split(strings.TrimSpace(string(modifiedContent)), "\n")
+
+	tasksByID := make(map[int]types.Task, len(taskList))
+	for _, task := range taskList {
+		tasksByID[task.ID] = task
+	}
 	
-	// Execute commands
+	// Collect commands and apply them with a single import, commands the import can not express run as they are
 	fmt.Println(theme.Title("\n=== Executing Commands ==="))
-	for i, command := range modifiedCommands {
+	var changes []taskwarrior.TaskChange
+	var direct [][]string
+	for _, command := range modifiedCommands {
 		command = strings.TrimSpace(command)
 		if command == "" || strings.HasPrefix(command, "#") {
 			continue // Skip empty lines and comments
 		}
+
+		if i, ok := generated[command]; ok {
+			changes = append(changes, taskwarrior.ChangeFromSuggestion(taskList[i], suggestions.TaskAnalyses[i]))
+			continue
+		}
 		
-		fmt.Printf("Executing: %s\n", command)
-		
-		// Parse and execute the command
-		parts := strings.Fields(command)
+		// Parse the command
+		parts, err := utils.SplitArgs(command)
+		if err != nil {
+			fmt.Printf(theme.Warn("Skipping invalid command (%v): %s\n"), err, command)
+			continue
+		}
 		if len(parts) < 3 || parts[0] != "task" || parts[1] != "modify" {
 			fmt.Printf(theme.Warn("Skipping invalid command: %s\n"), command)
 			continue
@@ -365,16 +458,91 @@ func massEditViaEditor(client taskwarrior.Client, taskList []types.Task, suggest
 			fmt.Printf(theme.Error("Invalid task ID in command: %s\n"), command)
 			continue
 		}
+
+		task, ok := tasksByID[taskID]
+		if !ok {
+			fmt.Printf(theme.Warn("Skipping task that was not analyzed: %s\n"), command)
+			continue
+		}
 		
-		args := parts[3:]
-		_, err = client.ModifyTaskInTaskWarrior(taskID, args)
-		if err != nil {
-			fmt.Printf(theme.Error("Command %d failed: %s\n"), i+1, err.Error())
-		} else {
-			fmt.Printf(theme.Success("Command %d executed successfully\n"), i+1)
+		change, ok := taskwarrior.ChangeFromArgs(task, parts[3:])
+		if !ok {
+			direct = append(direct, parts[2:])
+			continue
 		}
+		if change.IsEmpty() {
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	// The import goes first, it checks for concurrent edits against the fetched modification time
+	if len(changes) > 0 || len(direct) == 0 {
+		if err := applyTaskChanges(client, reader, taskList, changes, reanalyze); err != nil {
+			return err
+		}
+	}
+	return applyDirectCommands(client, reader, tasksByID, direct, reanalyze)
+}
+
+// applyDirectCommands runs edited commands the import can not express through `task modify`.
+// Tasks modified since they were fetched are shown to the user first, like on import.
+func applyDirectCommands(
+	client taskwarrior.Client,
+	reader *bufio.Reader,
+	tasksByID map[int]types.Task,
+	direct [][]string,
+	reanalyze func([]types.Task) error,
+) error {
+	if len(direct) == 0 {
+		return nil
+	}
+
+	var fetched []types.Task
+	for _, parts := range direct {
+		taskID, _ := strconv.Atoi(parts[0])
+		fetched = append(fetched, tasksByID[taskID])
+	}
+	stale, err := client.FindStale(fetched)
+	if err != nil {
+		return err
+	}
+	staleByUUID := make(map[string]taskwarrior.StaleTask, len(stale))
+	for _, task := range stale {
+		staleByUUID[task.Fetched.UUID] = task
+	}
+
+	// Several commands for one task share the answer
+	actions := make(map[string]conflictAction)
+	var outdated []types.Task
+	for i, parts := range direct {
+		if task, ok := staleByUUID[fetched[i].UUID]; ok {
+			action, asked := actions[task.Fetched.UUID]
+			if !asked {
+				action = askConflictAction(reader, task.Fetched, task.Current)
+				actions[task.Fetched.UUID] = action
+				if action == conflictReanalyze {
+					outdated = append(outdated, task.Current)
+				}
+			}
+			switch action {
+			case conflictReanalyze:
+				continue
+			case conflictSkip:
+				fmt.Println(theme.Warn(fmt.Sprintf("Skipped task %d", task.Current.ID)))
+				continue
+			}
+		}
+
+		fmt.Printf("Executing: task modify %s\n", strings.Join(parts, " "))
+		if _, err := client.ModifyTaskInTaskWarrior(fetched[i].ID, parts[1:]); err != nil {
+			fmt.Println(theme.Error(err.Error()))
+		}
+	}
+
+	if len(outdated) > 0 {
+		return reanalyze(outdated)
 	}
-	
 	return nil
 }
 
@@ -401,5 +569,6 @@ func showFilterExamples() {
 	fmt.Printf("  %s %s\n", theme.Info("Priority:"), "vanguard analyze priority:H")
 	fmt.Printf("  %s %s\n", theme.Info("Combination:"), "vanguard analyze project:work +urgent priority:H")
 	fmt.Printf("  %s %s\n", theme.Info("Description:"), "vanguard analyze /meeting/")
+	fmt.Printf("  %s %s\n", theme.Info("Preset:"), "vanguard analyze @work (presets are defined in vanguardrc.yaml)")
 	fmt.Println()
 }
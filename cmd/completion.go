@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taskvanguard/taskvanguard/internal/config"
+	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
+)
+
+// completeFilterPresets offers the configured @presets for commands taking TaskWarrior filter arguments
+func completeFilterPresets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var matches []string
+	for _, name := range taskwarrior.PresetNames(cfg) {
+		if strings.HasPrefix(name, toComplete) {
+			matches = append(matches, name)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAuditArgs completes the audited command first and filter presets after it
+func completeAuditArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return []string{"analyze", "spot", "add", "guide", "goals"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	if args[0] == "goals" {
+		if len(args) == 1 {
+			return []string{"align", "review"}, cobra.ShellCompDirectiveNoFileComp
+		}
+		if args[1] == "review" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+	if args[0] == "add" || args[0] == "guide" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeFilterPresets(cmd, args, toComplete)
+}
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taskvanguard/taskvanguard/internal/config"
+	"github.com/taskvanguard/taskvanguard/internal/prompts"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect, change and validate the configuration",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var configCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Validate the config and report every problem",
+	Long: `Loads the system, user and project configs over the built-in defaults, migrates them to the current
+config_version if needed, validates all settings, checks that an API key can be found for every LLM command
+and parses the prompt template overrides.
+Exits with status 1 if a problem was found.`,
+	Run: runConfigCheck,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a config value, e.g. settings.task_import_limit",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config value in the config file",
+	Long: `Sets a dotted key in the config file, e.g. settings.task_import_limit 200 or llm.commands.spot.model gpt-4.1.
+Lists take comma separated values (filters.tag_filter_tags private,secret), sections take YAML flow style.
+The file keeps its comments and is only written if the result is valid.`,
+	Args: cobra.ExactArgs(2),
+	Run:  runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a value from the config file so the default applies again",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigUnset,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR and validate it on save",
+	Run:   runConfigEdit,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the config file, or with --effective the merged settings and their sources",
+	Long: `Without flags prints the config files. With --effective prints every setting a command runs with,
+merged from the defaults, the system, user and project configs, TASKVANGUARD_* variables and flags,
+and where each value came from.
+Use --command to include the llm.commands block of a command and --model/--provider to preview the flags.`,
+	Run: runConfigShow,
+}
+
+func init() {
+	configCmd.AddCommand(configCheckCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configShowCmd)
+
+	for _, cmd := range []*cobra.Command{configSetCmd, configUnsetCmd, configEditCmd} {
+		cmd.Flags().Bool("project", false, "Change the nearest "+config.ProjectFileName+" instead of the user config")
+	}
+	configShowCmd.Flags().Bool("effective", false, "Show the merged settings with the source of each value")
+	configShowCmd.Flags().String("command", "", "Show the settings for this command ("+strings.Join(config.LLMCommands, ", ")+")")
+	addLLMFlags(configShowCmd)
+}
+
+func runConfigCheck(cmd *cobra.Command, args []string) {
+	loaded, err := config.LoadLayers()
+	if err != nil {
+		fmt.Printf("%s %v\n", theme.Error("✗"), err)
+		os.Exit(1)
+	}
+	if len(loaded.Layers) == 0 {
+		configPath, _ := config.Path()
+		fmt.Println(theme.Warn(fmt.Sprintf("No config file at %s, using defaults. Run 'vanguard init' to create one.", configPath)))
+	}
+	for _, layer := range loaded.Layers {
+		fmt.Printf("%s %s config %s\n", theme.Success("✓"), layer.Name, layer.Path)
+	}
+	cfg := loaded.Config
+
+	failed := false
+
+	if err := config.Validate(cfg); err != nil {
+		failed = true
+		var validationErr *config.ValidationError
+		if errors.As(err, &validationErr) {
+			for _, problem := range validationErr.Problems {
+				fmt.Printf("%s %s (%s)\n", theme.Error("✗"), problem, problemSource(loaded, problem))
+			}
+		} else {
+			fmt.Printf("%s %v\n", theme.Error("✗"), err)
+		}
+	} else {
+		fmt.Printf("%s %s\n", theme.Success("✓"), "settings are valid")
+	}
+
+	for _, command := range config.LLMCommands {
+		llm := cfg.LLM.ForCommand(command)
+		if llm.Provider == "ollama" {
+			continue
+		}
+		key, err := config.ResolveAPIKey(llm)
+		switch {
+		case err != nil:
+			failed = true
+			fmt.Printf("%s %s: %v\n", theme.Error("✗"), command, err)
+		case key == "":
+			failed = true
+			fmt.Printf("%s %s: no API key found for provider %s\n", theme.Error("✗"), command, llm.Provider)
+		}
+	}
+	if !failed {
+		fmt.Printf("%s %s\n", theme.Success("✓"), "API key found")
+	}
+
+	if overrides, _ := config.UserTemplateNames(); len(overrides) > 0 {
+		for _, name := range overrides {
+			if replacement, ok := config.RetiredTemplates[name]; ok {
+				fmt.Printf("%s %s is not used anymore, move your changes to %s\n", theme.Warn("!"), name, replacement)
+				continue
+			}
+			if _, err := prompts.LoadPrompt(name); err != nil {
+				failed = true
+				fmt.Printf("%s %v\n", theme.Error("✗"), err)
+			}
+		}
+		dir, _ := config.TemplatesDir()
+		fmt.Printf("%s %d template overrides in %s\n", theme.Info("•"), len(overrides), dir)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// problemSource names the layer that set the key a validation problem starts with
+func problemSource(loaded *config.Loaded, problem string) string {
+	key := strings.FieldsFunc(problem, func(r rune) bool { return r == ' ' || r == ':' || r == '[' })[0]
+	for candidate := key; candidate != ""; {
+		if source, ok := loaded.Sources[candidate]; ok {
+			return source
+		}
+		i := strings.LastIndex(candidate, ".")
+		if i < 0 {
+			break
+		}
+		candidate = candidate[:i]
+	}
+	return "default"
+}
+
+// configPathOrExit returns the config file set, unset and edit change: the user config or with --project
+// the nearest .vanguardrc.yaml. Missing files are created, the user config with the defaults.
+func configPathOrExit(cmd *cobra.Command) string {
+	if project, _ := cmd.Flags().GetBool("project"); project {
+		projectPath, err := config.ProjectPath()
+		if err != nil {
+			fmt.Println(theme.Error(err.Error()))
+			os.Exit(1)
+		}
+		if projectPath == "" {
+			projectPath = config.ProjectFileName
+			content := fmt.Sprintf("# TaskVanguard settings for this directory, merged over the user config\nconfig_version: %d\n", config.CurrentVersion)
+			if err := os.WriteFile(projectPath, []byte(content), 0600); err != nil {
+				fmt.Println(theme.Error(fmt.Sprintf("Failed to create %s: %v", projectPath, err)))
+				os.Exit(1)
+			}
+			fmt.Println(theme.Info("Created " + projectPath))
+		}
+		return projectPath
+	}
+
+	configPath, err := config.Path()
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if _, err := config.CreateDefaultConfig(configPath); err != nil {
+			fmt.Println(theme.Error(fmt.Sprintf("Failed to create config: %v", err)))
+			os.Exit(1)
+		}
+		fmt.Println(theme.Info("Created default config at " + configPath))
+	}
+	return configPath
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) {
+	loaded, err := config.LoadLayers()
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+
+	value, err := config.GetKey(loaded.Config, args[0])
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	fmt.Println(config.FormatValue(config.MaskSecrets(args[0], value)))
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) {
+	configPath := configPathOrExit(cmd)
+	if err := config.SetKey(configPath, args[0], args[1]); err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	fmt.Println(theme.Success(fmt.Sprintf("✓ %s set in %s", args[0], configPath)))
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) {
+	configPath := configPathOrExit(cmd)
+	if err := config.UnsetKey(configPath, args[0]); err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	fmt.Println(theme.Success(fmt.Sprintf("✓ %s removed from %s, the default applies", args[0], configPath)))
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) {
+	configPath := configPathOrExit(cmd)
+	if err := editConfig(configPath); err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+}
+
+// editConfig lets the user edit a copy of the config file and only replaces the file once the copy is valid
+func editConfig(configPath string) error {
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(configPath), "vanguardrc-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.Write(original); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	tempFile.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "nano" // fallback to nano
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		editorCmd := exec.Command(editor, tempFile.Name())
+		editorCmd.Stdin = os.Stdin
+		editorCmd.Stdout = os.Stdout
+		editorCmd.Stderr = os.Stderr
+		if err := editorCmd.Run(); err != nil {
+			return fmt.Errorf("editor failed: %w", err)
+		}
+
+		edited, err := os.ReadFile(tempFile.Name())
+		if err != nil {
+			return err
+		}
+		if string(edited) == string(original) {
+			fmt.Println(theme.Info("No changes"))
+			return nil
+		}
+
+		if filepath.Base(configPath) == config.ProjectFileName {
+			err = config.ValidateProjectData(edited)
+		} else {
+			err = config.ValidateData(edited)
+		}
+		if err == nil {
+			if err := os.WriteFile(configPath, edited, 0600); err != nil {
+				return err
+			}
+			fmt.Println(theme.Success("✓ Config saved to " + configPath))
+			return nil
+		}
+
+		fmt.Println(theme.Error(err.Error()))
+		fmt.Print("Edit again? [Y]es/[n]o (discard changes): ")
+		answer, _ := reader.ReadString('\n')
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "n") {
+			fmt.Println(theme.Warn("Changes discarded"))
+			return nil
+		}
+	}
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) {
+	loaded, err := config.LoadLayers()
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+
+	if effective, _ := cmd.Flags().GetBool("effective"); !effective {
+		if len(loaded.Layers) == 0 {
+			fmt.Println(theme.Warn("No config file found, using defaults. Run 'vanguard init' to create one."))
+		}
+		for i, layer := range loaded.Layers {
+			data, err := os.ReadFile(layer.Path)
+			if err == nil {
+				data, err = config.MaskFile(data)
+			}
+			if err != nil {
+				fmt.Println(theme.Error(err.Error()))
+				os.Exit(1)
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(theme.Title(fmt.Sprintf("# %s config: %s", layer.Name, layer.Path)))
+			fmt.Print(string(data))
+		}
+		return
+	}
+
+	command, _ := cmd.Flags().GetString("command")
+	var cli types.LLMOverride
+	cli.Model, _ = cmd.Flags().GetString("model")
+	cli.Provider, _ = cmd.Flags().GetString("provider")
+
+	values, err := config.Effective(loaded, command, cli)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+
+	for _, layer := range loaded.Layers {
+		fmt.Println(theme.Title(fmt.Sprintf("%s config: %s", layer.Name, layer.Path)))
+	}
+
+	width := 0
+	for _, value := range values {
+		width = max(width, len(value.Key))
+	}
+	for _, value := range values {
+		line := fmt.Sprintf("%-*s  %s", width, value.Key, config.FormatValue(value.Value))
+		if value.Source == "default" {
+			fmt.Printf("%s  %s\n", line, theme.Unimportant("("+value.Source+")"))
+		} else {
+			fmt.Printf("%s  %s\n", line, theme.Info("("+value.Source+")"))
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+type conflictAction int
+
+const (
+	conflictSkip conflictAction = iota
+	conflictForce
+	conflictReanalyze
+)
+
+// askConflictAction shows how a task was changed elsewhere since it was analyzed and asks how to continue
+func askConflictAction(reader *bufio.Reader, before, after types.Task) conflictAction {
+	fmt.Printf("\n%s Task %d was modified since it was analyzed: %s\n", theme.Warn("⚠"), after.ID, after.Description)
+	changes := taskwarrior.DescribeChanges(before, after)
+	if len(changes) == 0 {
+		changes = []string{"modification time only"}
+	}
+	for _, change := range changes {
+		fmt.Printf("  %s %s\n", theme.Warn("▸"), change)
+	}
+
+	fmt.Print("Re-analyze, apply anyway or skip? [r]e-analyze/[f]orce/[S]kip: ")
+	input, _ := reader.ReadString('\n')
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	switch input {
+	case "r", "re-analyze", "reanalyze":
+		return conflictReanalyze
+	case "f", "force":
+		return conflictForce
+	default:
+		return conflictSkip
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/taskvanguard/taskvanguard/internal/goals"
 	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
 	"github.com/taskvanguard/taskvanguard/pkg/theme"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
 )
 
 // getGoalsManager creates a goals manager with the current config
@@ -37,10 +39,20 @@ func init() {
 	goalsCmd.AddCommand(goalsShowCmd)
 	goalsCmd.AddCommand(goalsModifyCmd)
 	goalsCmd.AddCommand(goalsDeleteCmd)
+	goalsCmd.AddCommand(goalsDoneCmd)
 	goalsCmd.AddCommand(goalsLinkCmd)
 	goalsCmd.AddCommand(goalsUnlinkCmd)
 	goalsCmd.AddCommand(goalsLinksCmd)
-	// goalsCmd.AddCommand(goalsAlignCmd)
+	goalsCmd.AddCommand(goalsProgressCmd)
+	goalsCmd.AddCommand(goalsReviewCmd)
+	goalsCmd.AddCommand(goalsAlignCmd)
+	goalsCmd.AddCommand(goalsDoctorCmd)
+	goalsCmd.AddCommand(goalsHistoryCmd)
+
+	for _, cmd := range []*cobra.Command{goalsAddCmd, goalsModifyCmd} {
+		cmd.Flags().String("due", "", "Target date of the goal, any TaskWarrior date like 2026-12-31 or eoy")
+		cmd.Flags().Int("review", 0, "Review the goal every this many days, 0 resets it to goal_review_days")
+	}
 }
 
 var goalsListCmd = &cobra.Command{
@@ -53,24 +65,85 @@ var goalsListCmd = &cobra.Command{
 			return
 		}
 
-		goals, err := goalsManager.ListGoals()
+		tree, err := goalsManager.GoalTree()
 		if err != nil {
 			color.Red("Error listing goals: %v", err)
 			return
 		}
 
-		if len(goals) == 0 {
+		if len(tree) == 0 {
 			color.Yellow("No goals found.")
 			return
 		}
 
 		color.Green("Goals:")
-		for _, goal := range goals {
-			fmt.Printf("  %d: %s\n", goal.ID, goal.Description)
-		}
+		printGoalTree(tree)
 	},
 }
 
+// goalRow is a line of the goal table, the label holds the tree branches
+type goalRow struct {
+	label string
+	node  *goals.GoalNode
+}
+
+// goalRows flattens the goal tree, indenting sub-goals below their parent
+func goalRows(nodes []*goals.GoalNode, prefix string, top bool) []goalRow {
+	var rows []goalRow
+	for i, node := range nodes {
+		branch, indent := "", ""
+		if !top {
+			branch, indent = "├─ ", "│  "
+			if i == len(nodes)-1 {
+				branch, indent = "└─ ", "   "
+			}
+		}
+		rows = append(rows, goalRow{label: fmt.Sprintf("%s%s%s: %s", prefix, branch, goalLabel(node.Goal), node.Goal.Description), node: node})
+		rows = append(rows, goalRows(node.Children, prefix+indent, false)...)
+	}
+	return rows
+}
+
+// printGoalTree prints goals with their sub-goals indented below them and the progress rolled up from linked tasks
+func printGoalTree(nodes []*goals.GoalNode) {
+	rows := goalRows(nodes, "  ", true)
+	width := len("  Goal")
+	for _, row := range rows {
+		width = max(width, utf8.RuneCountInString(row.label))
+	}
+
+	fmt.Println(theme.Unimportant(fmt.Sprintf("%-*s  %5s  %7s  %4s  %8s  %s", width, "  Goal", "Done", "Pending", "%", "Per week", "Projected")))
+	for _, row := range rows {
+		p := row.node.Progress
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(row.label))
+		if p.Completed+p.Pending == 0 {
+			fmt.Printf("%s%s  %s\n", row.label, padding, theme.Unimportant("no linked tasks"))
+			continue
+		}
+		fmt.Printf("%s%s  %5d  %7d  %3d%%  %8.1f  %s\n", row.label, padding, p.Completed, p.Pending, p.Percent(), p.Velocity, projectedDate(p))
+	}
+}
+
+// projectedDate formats the projected completion of a goal
+func projectedDate(p goals.Progress) string {
+	switch {
+	case p.Pending == 0:
+		return "done"
+	case p.Projected == nil:
+		return "-"
+	default:
+		return p.Projected.Format("2006-01-02")
+	}
+}
+
+// goalLabel returns the ID of a goal, or the short UUID for completed goals which have no ID
+func goalLabel(goal types.Task) string {
+	if goal.ID == 0 && len(goal.UUID) >= 8 {
+		return goal.UUID[:8]
+	}
+	return strconv.Itoa(goal.ID)
+}
+
 var goalsAddCmd = &cobra.Command{
 	Use:   "add [goal description]",
 	Short: "Add a new goal",
@@ -82,7 +155,7 @@ var goalsAddCmd = &cobra.Command{
 			return
 		}
 
-		output, taskID, err := goalsManager.AddGoal(args)
+		output, taskID, err := goalsManager.AddGoal(append(args, goalScheduleArgs(cmd)...))
 		if err != nil {
 			color.Red("Error adding goal: %v", err)
 			return
@@ -151,13 +224,38 @@ var goalsShowCmd = &cobra.Command{
 		}
 		
 		fmt.Printf("  %s %.2f\n", theme.Info("Urgency:"), task.Urgency)
+
+		if isGoal {
+			if task.Due != nil {
+				fmt.Printf("  %s %s\n", theme.Info("Due:"), task.Due.Time().Local().Format("2006-01-02"))
+			}
+			fmt.Printf("  %s %d days\n", theme.Info("Review every:"), int(goals.ReviewInterval(*task, goalsManager.Settings()).Hours()/24))
+			if task.Reviewed != nil {
+				fmt.Printf("  %s %s\n", theme.Info("Last reviewed:"), task.Reviewed.Time().Local().Format("2006-01-02"))
+			}
+		}
 	},
 }
 
+// goalScheduleArgs turns the --due and --review flags into TaskWarrior arguments
+func goalScheduleArgs(cmd *cobra.Command) []string {
+	var args []string
+	if due, _ := cmd.Flags().GetString("due"); due != "" {
+		args = append(args, "due:"+due)
+	}
+	if review, _ := cmd.Flags().GetInt("review"); review > 0 {
+		args = append(args, fmt.Sprintf("review:%d", review))
+	} else if cmd.Flags().Changed("review") {
+		// Clearing the UDA brings back the default interval
+		args = append(args, "review:")
+	}
+	return args
+}
+
 var goalsModifyCmd = &cobra.Command{
 	Use:   "modify <goal_id> [arguments...]",
 	Short: "Modify an existing goal",
-	Args:  cobra.MinimumNArgs(2),
+	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		goalsManager, err := getGoalsManager(cmd)
 		if err != nil {
@@ -171,7 +269,13 @@ var goalsModifyCmd = &cobra.Command{
 			return
 		}
 
-		output, err := goalsManager.ModifyGoal(goalID, args[1:])
+		modifications := append(args[1:], goalScheduleArgs(cmd)...)
+		if len(modifications) == 0 {
+			color.Red("Nothing to modify, pass TaskWarrior arguments, --due or --review")
+			return
+		}
+
+		output, err := goalsManager.ModifyGoal(goalID, modifications)
 		if err != nil {
 			color.Red("Error modifying goal: %v", err)
 			return
@@ -185,28 +289,22 @@ var goalsModifyCmd = &cobra.Command{
 var goalsDeleteCmd = &cobra.Command{
 	Use:   "delete <goal_id>",
 	Short: "Delete a goal",
-	Args:  cobra.ExactArgs(1),
+	Long: `Deletes a goal after asking for confirmation (skip it with --yes). If tasks or sub-goals are
+linked to it you can relink them to another goal, unlink them or delete them as well
+(--orphans relink|unlink|cascade), so no task is left pointing to a deleted goal.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		goalsManager, err := getGoalsManager(cmd)
-		if err != nil {
-			color.Red("Error initializing goals manager: %v", err)
-			return
-		}
-
-		err = goalsManager.DeleteGoal(args[0])
-		if err != nil {
-			color.Red("Error deleting goal: %v", err)
-			return
-		}
-
-		color.Green("Goal deleted successfully.")
+		closeGoal(cmd, args[0], "delete")
 	},
 }
 
 var goalsLinkCmd = &cobra.Command{
 	Use:   "link <id1> <id2>",
-	Short: "Link a task and goal together (order-agnostic)",
-	Args:  cobra.ExactArgs(2),
+	Short: "Link a task and goal together (order-agnostic), or make goal id1 a sub-goal of goal id2",
+	Long: `Links a task and a goal in any order, or makes goal id1 a sub-goal of goal id2.
+Goals can also be given by name or a fuzzy query, e.g. 'vanguard goals link 42 marathon'.
+If several goals match you are asked which one is meant.`,
+	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		goalsManager, err := getGoalsManager(cmd)
 		if err != nil {
@@ -214,7 +312,13 @@ var goalsLinkCmd = &cobra.Command{
 			return
 		}
 
-		err = goalsManager.Link(args[0], args[1])
+		ids, err := resolveGoalArgs(goalsManager, args)
+		if err != nil {
+			color.Red("Error: %v", err)
+			return
+		}
+
+		err = goalsManager.Link(ids[0], ids[1])
 		if err != nil {
 			color.Red("Error linking task and goal: %v", err)
 			return
@@ -226,7 +330,7 @@ var goalsLinkCmd = &cobra.Command{
 
 var goalsUnlinkCmd = &cobra.Command{
 	Use:   "unlink <id1> <id2>",
-	Short: "Remove link between task and goal (order-agnostic)",
+	Short: "Remove link between task and goal (order-agnostic), goals may be given by name",
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		goalsManager, err := getGoalsManager(cmd)
@@ -235,7 +339,13 @@ var goalsUnlinkCmd = &cobra.Command{
 			return
 		}
 
-		err = goalsManager.Unlink(args[0], args[1])
+		ids, err := resolveGoalArgs(goalsManager, args)
+		if err != nil {
+			color.Red("Error: %v", err)
+			return
+		}
+
+		err = goalsManager.Unlink(ids[0], ids[1])
 		if err != nil {
 			color.Red("Error unlinking task and goal: %v", err)
 			return
@@ -285,11 +395,83 @@ var goalsLinksCmd = &cobra.Command{
 	},
 }
 
-// var goalsAlignCmd = &cobra.Command{
-// 	Use:   "align",
-// 	Short: "Show how current tasks align with goals",
-// 	Run: func(cmd *cobra.Command, args []string) {
-// 		// TODO: Implement alignment analysis
-// 		color.Yellow("Goal alignment analysis not yet implemented.")
-// 	},
-// }
\ No newline at end of file
+// burndownWidth is the width of the longest burndown bar
+const burndownWidth = 40
+
+var goalsProgressCmd = &cobra.Command{
+	Use:   "progress <goal_id>",
+	Short: "Show progress, velocity, projected completion and a burndown of a goal",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		goalsManager, err := getGoalsManager(cmd)
+		if err != nil {
+			color.Red("Error initializing goals manager: %v", err)
+			return
+		}
+
+		ids, err := resolveGoalArgs(goalsManager, args)
+		if err != nil {
+			color.Red("Error: %v", err)
+			return
+		}
+
+		node, err := goalsManager.GoalProgress(ids[0])
+		if err != nil {
+			color.Red("Error getting goal progress: %v", err)
+			return
+		}
+		p := node.Progress
+
+		fmt.Println(theme.Title("\n───────────────────────────────────────────────"))
+		fmt.Println(theme.Title("          🏔️ GOAL PROGRESS:"))
+		fmt.Println(theme.Title("───────────────────────────────────────────────"))
+		fmt.Printf("  %s %s\n", theme.Info("Goal:"), node.Goal.Description)
+		if len(node.Children) > 0 {
+			fmt.Printf("  %s %d, their tasks are included\n", theme.Info("Sub-goals:"), len(node.Children))
+		}
+		if p.Completed+p.Pending == 0 {
+			fmt.Println(theme.Warn("\nNo tasks linked to this goal yet. Link some with 'vanguard goals link <task_id> <goal_id>'."))
+			return
+		}
+
+		fmt.Printf("  %s %d done, %d pending\n", theme.Info("Tasks:"), p.Completed, p.Pending)
+		fmt.Printf("  %s %d%% (%.1f of %.1f estimated)\n", theme.Info("Completion:"), p.Percent(), p.CompletedEstimate, p.TotalEstimate)
+		fmt.Printf("  %s %.1f per week over the last %d weeks\n", theme.Info("Velocity:"), p.Velocity, goalsManager.VelocityWeeks())
+		fmt.Printf("  %s %s\n", theme.Info("Projected completion:"), projectedDate(p))
+
+		printBurndown(p)
+	},
+}
+
+// printBurndown draws the remaining estimate per week as horizontal bars, followed by the projection at the current velocity
+func printBurndown(p goals.Progress) {
+	if len(p.Burndown) == 0 {
+		return
+	}
+
+	peak := 0.0
+	for _, point := range p.Burndown {
+		peak = max(peak, point.Remaining)
+	}
+	if peak == 0 {
+		return
+	}
+	bar := func(remaining float64, fill string) string {
+		return strings.Repeat(fill, int(remaining/peak*burndownWidth+0.5))
+	}
+
+	fmt.Println(theme.Title("\n  Burndown (remaining estimate):"))
+	for _, point := range p.Burndown {
+		fmt.Printf("  %s │%s %.1f\n", point.Date.Format("2006-01-02"), theme.Success(bar(point.Remaining, "█")), point.Remaining)
+	}
+
+	if p.Projected == nil {
+		return
+	}
+	last := p.Burndown[len(p.Burndown)-1]
+	for week, remaining := 1, last.Remaining; remaining > 0 && week <= 8; week++ {
+		remaining = max(0, remaining-p.Velocity)
+		date := last.Date.AddDate(0, 0, 7*week)
+		fmt.Printf("  %s │%s %.1f\n", date.Format("2006-01-02"), theme.Unimportant(bar(remaining, "░")), remaining)
+	}
+}
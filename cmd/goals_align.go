@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+	"github.com/taskvanguard/taskvanguard/internal/analyzer"
+	"github.com/taskvanguard/taskvanguard/internal/goals"
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
+	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+var goalsAlignCmd = &cobra.Command{
+	Use:   "align [filter | @preset...]",
+	Short: "Show how current tasks align with goals",
+	Long: `Sends the pending tasks not linked to any goal together with your goals to the LLM, which proposes
+the goals each task supports with a confidence and a rationale. Also lists the goals no task works
+towards and the tasks that support no goal. Accept the proposed links one by one or all at once,
+or pass --yes to link every proposal at or above --min-confidence.`,
+	ValidArgsFunction: completeFilterPresets,
+	Run:               runGoalsAlign,
+}
+
+func init() {
+	goalsAlignCmd.Flags().Float64("min-confidence", 0.5, "Hide proposed links below this confidence (0 to 1)")
+	goalsAlignCmd.Flags().BoolP("yes", "y", false, "Link all shown proposals without asking")
+	addLLMFlags(goalsAlignCmd)
+}
+
+func runGoalsAlign(cmd *cobra.Command, args []string) {
+	minConfidence, _ := cmd.Flags().GetFloat64("min-confidence")
+	acceptAll, _ := cmd.Flags().GetBool("yes")
+
+	env, err := taskwarrior.Bootstrap(cmd)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		return
+	}
+	goalsManager := goals.NewManager(env.Config)
+	settings := env.Config.Settings
+
+	tree, err := goalsManager.GoalTree()
+	if err != nil {
+		fmt.Println(theme.Error(fmt.Sprintf("Error listing goals: %v", err)))
+		return
+	}
+	pendingGoals := pendingGoalNodes(tree)
+	if len(pendingGoals) == 0 {
+		fmt.Println(theme.Warn("No pending goals found. Add one with 'vanguard goals add'."))
+		return
+	}
+
+	gate, err := privacy.NewGate(env.Config)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		return
+	}
+
+	tasks, blocked, err := alignCandidates(env, gate, args)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		return
+	}
+	if len(blocked) > 0 {
+		fmt.Println(theme.Unimportant(fmt.Sprintf("%d tasks excluded by privacy filters", len(blocked))))
+	}
+	if len(tasks) > settings.TaskImportLimit {
+		tasks = tasks[:settings.TaskImportLimit]
+		fmt.Printf("Limited to first %d tasks for alignment\n", settings.TaskImportLimit)
+	}
+
+	var alignment *analyzer.GoalAlignment
+	if len(tasks) == 0 {
+		fmt.Println(theme.Success("✓ Every pending task is linked to a goal."))
+		alignment = &analyzer.GoalAlignment{}
+	} else {
+		goalTasks := goalsOf(pendingGoals)
+
+		s := spinner.New(spinner.CharSets[40], 100*time.Millisecond)
+		s.Prefix = fmt.Sprintf("→ Aligning %d tasks with %d goals... ", len(tasks), len(goalTasks))
+		s.Start()
+		alignment, err = analyzer.AlignTasksWithGoals(gate, tasks, goalTasks, settings.TaskProcessingBatchSize)
+		s.Stop()
+		if err != nil {
+			fmt.Printf("%s %s\n", theme.Error("❌ Alignment failed:"), err.Error())
+			return
+		}
+	}
+
+	links := shownLinks(alignment.Links, minConfidence)
+	printAlignment(alignment, links, tasks, pendingGoals)
+
+	if len(links) == 0 {
+		return
+	}
+	if !acceptAll {
+		accepted, ok := chooseLinks(links)
+		if !ok {
+			return
+		}
+		links = accepted
+	}
+
+	linked := 0
+	for _, link := range links {
+		if err := goalsManager.LinkTaskToGoal(link.Task.UUID, link.Goal.UUID); err != nil {
+			fmt.Println(theme.Error(fmt.Sprintf("Failed to link task %d to goal %d: %v", link.Task.ID, link.Goal.ID, err)))
+			continue
+		}
+		linked++
+	}
+	fmt.Println(theme.Success(fmt.Sprintf("✅ Linked %d of %d proposals", linked, len(links))))
+}
+
+// pendingGoalNodes returns the pending goals of the tree, which tasks may be linked to
+func pendingGoalNodes(tree []*goals.GoalNode) []*goals.GoalNode {
+	var pending []*goals.GoalNode
+	for _, row := range goalRows(tree, "", true) {
+		if row.node.Goal.Status == "pending" {
+			pending = append(pending, row.node)
+		}
+	}
+	return pending
+}
+
+func goalsOf(nodes []*goals.GoalNode) []types.Task {
+	goalTasks := make([]types.Task, len(nodes))
+	for i, node := range nodes {
+		goalTasks[i] = node.Goal
+	}
+	return goalTasks
+}
+
+// alignCandidates returns the pending tasks matching args that are not linked to a goal,
+// together with the tasks blocked by the privacy filters
+func alignCandidates(env *taskwarrior.RuntimeContext, gate *privacy.Gate, args []string) ([]types.Task, []privacy.Blocked, error) {
+	filterArgs, err := env.Client.ResolveFilter(env.Config, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	candidates, err := env.Client.GetPendingTasksWithArgs(append(filterArgs, "goal.none:"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch tasks: %w", err)
+	}
+	var unlinked []types.Task
+	for _, task := range candidates {
+		if !env.Config.Settings.IsGoal(task) {
+			unlinked = append(unlinked, task)
+		}
+	}
+	tasks, blocked := gate.Partition(unlinked)
+	return tasks, blocked, nil
+}
+
+// shownLinks returns the proposals at or above minConfidence, most confident first
+func shownLinks(links []analyzer.GoalLink, minConfidence float64) []analyzer.GoalLink {
+	var shown []analyzer.GoalLink
+	for _, link := range links {
+		if link.Confidence >= minConfidence {
+			shown = append(shown, link)
+		}
+	}
+	sort.SliceStable(shown, func(i, j int) bool {
+		return shown[i].Confidence > shown[j].Confidence
+	})
+	return shown
+}
+
+// printAlignment prints the proposed links, the tasks supporting no goal and the goals no task works towards
+func printAlignment(alignment *analyzer.GoalAlignment, links []analyzer.GoalLink, tasks []types.Task, pendingGoals []*goals.GoalNode) {
+	supported := make(map[string]bool)
+	proposedTasks := make(map[string]bool)
+	for _, link := range alignment.Links {
+		supported[link.Goal.UUID] = true
+		proposedTasks[link.Task.UUID] = true
+	}
+
+	if len(links) > 0 {
+		fmt.Println(theme.Title("\n🔗 Proposed links:"))
+		for i, link := range links {
+			fmt.Printf("  %d. %s %s → %s %s %s\n", i+1,
+				theme.Info(strconv.Itoa(link.Task.ID)+":"), link.Task.Description,
+				theme.Info(goalLabel(link.Goal)+":"), link.Goal.Description,
+				theme.Unimportant(fmt.Sprintf("(%.0f%%)", link.Confidence*100)))
+			if link.Rationale != "" {
+				fmt.Printf("     %s\n", theme.Unimportant(link.Rationale))
+			}
+		}
+	}
+	if hidden := len(alignment.Links) - len(links); hidden > 0 {
+		fmt.Println(theme.Unimportant(fmt.Sprintf("%d proposals below --min-confidence hidden", hidden)))
+	}
+
+	notes := make(map[string]string)
+	for _, unaligned := range alignment.Unaligned {
+		notes[unaligned.Task.UUID] = unaligned.Note
+	}
+	var unaligned []types.Task
+	for _, task := range tasks {
+		if !proposedTasks[task.UUID] {
+			unaligned = append(unaligned, task)
+		}
+	}
+	if len(unaligned) > 0 {
+		fmt.Println(theme.Title("\n🧭 Tasks supporting no goal:"))
+		for _, task := range unaligned {
+			fmt.Printf("  %s %s", theme.Info(strconv.Itoa(task.ID)+":"), task.Description)
+			if note := notes[task.UUID]; note != "" {
+				fmt.Printf(" %s", theme.Unimportant("– "+note))
+			}
+			fmt.Println()
+		}
+	}
+
+	var unsupported []types.Task
+	for _, node := range pendingGoals {
+		if node.Progress.Pending == 0 && !supported[node.Goal.UUID] {
+			unsupported = append(unsupported, node.Goal)
+		}
+	}
+	if len(unsupported) > 0 {
+		fmt.Println(theme.Title("\n🏔️ Goals no task works towards:"))
+		for _, goal := range unsupported {
+			fmt.Printf("  %s %s\n", theme.Warn(goalLabel(goal)+":"), goal.Description)
+		}
+		fmt.Println(theme.Unimportant("Plan next actions with 'vanguard goals review' or 'vanguard guide'."))
+	}
+}
+
+// chooseLinks asks which proposals to link. ok is false if the user declined all of them.
+func chooseLinks(links []analyzer.GoalLink) (accepted []analyzer.GoalLink, ok bool) {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf("\n%s Link [a]ll/[i]nteractive/[N]one: ", theme.Title("→"))
+	input, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "a", "all":
+		return links, true
+	case "i", "interactive":
+	default:
+		return nil, false
+	}
+
+	for i, link := range links {
+		fmt.Printf("%s %s → %s %s [y]es/[N]o/[q]uit: ", theme.Title(fmt.Sprintf("(%d/%d)", i+1, len(links))),
+			link.Task.Description, link.Goal.Description, theme.Unimportant(fmt.Sprintf("(%.0f%%)", link.Confidence*100)))
+		input, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "y", "yes":
+			accepted = append(accepted, link)
+		case "q", "quit":
+			return accepted, len(accepted) > 0
+		}
+	}
+	return accepted, len(accepted) > 0
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/taskvanguard/taskvanguard/internal/goals"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+var goalsDoneCmd = &cobra.Command{
+	Use:   "done <goal_id>",
+	Short: "Mark a goal as achieved",
+	Long: `Marks a goal as done. If pending tasks or sub-goals are linked to it you can keep the links,
+relink them to another goal, unlink them or complete them as well (--orphans keep|relink|unlink|cascade).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		closeGoal(cmd, args[0], "done")
+	},
+}
+
+var goalsDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Find and repair tasks linked to deleted or missing goals",
+	Long: `Lists every task whose goal UDA refers to a goal that was deleted, never existed or is no goal.
+The dangling references can be removed or replaced by a link to another goal.`,
+	Args: cobra.NoArgs,
+	Run:  runGoalsDoctor,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{goalsDeleteCmd, goalsDoneCmd} {
+		cmd.Flags().String("orphans", "", "What to do with linked tasks without asking: relink, unlink or cascade (done also accepts keep)")
+		cmd.Flags().String("to", "", "Goal ID linked tasks are moved to with --orphans relink")
+	}
+	goalsDeleteCmd.Flags().BoolP("yes", "y", false, "Delete without asking for confirmation")
+	goalsDoctorCmd.Flags().BoolP("yes", "y", false, "Remove all dangling references without asking")
+	goalsDoctorCmd.Flags().String("to", "", "Link repaired tasks to this goal ID instead")
+}
+
+// closeGoal deletes a goal or marks it done (action "delete" or "done") after handling the tasks linked to it
+func closeGoal(cmd *cobra.Command, goalID, action string) {
+	orphans, _ := cmd.Flags().GetString("orphans")
+	target, _ := cmd.Flags().GetString("to")
+
+	goalsManager, err := getGoalsManager(cmd)
+	if err != nil {
+		color.Red("Error initializing goals manager: %v", err)
+		return
+	}
+
+	ids, err := resolveGoalArgs(goalsManager, []string{goalID})
+	if err != nil {
+		color.Red("Error: %v", err)
+		return
+	}
+	goal, err := goalsManager.GetGoal(ids[0])
+	if err != nil {
+		color.Red("Error: %v", err)
+		return
+	}
+
+	// TaskWarrior's own confirmation is turned off when deleting, so ask here
+	reader := bufio.NewReader(os.Stdin)
+	if confirmed, _ := cmd.Flags().GetBool("yes"); action == "delete" && !confirmed {
+		fmt.Printf("%s Delete goal %s %s? [y/N]: ", theme.Title("→"), theme.Info(goalLabel(*goal)+":"), goal.Description)
+		input, _ := reader.ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(input)); answer != "y" && answer != "yes" {
+			fmt.Println(theme.Warn("Aborted, the goal was not deleted."))
+			return
+		}
+	}
+
+	linked, err := goalsManager.GetLinkedTasks(goal.UUID)
+	if err != nil {
+		color.Red("Error getting linked tasks: %v", err)
+		return
+	}
+	var affected []types.Task
+	for _, task := range linked {
+		// Completed tasks stay linked to an achieved goal, but would point nowhere after deleting it
+		if task.Status == "pending" || (action == "delete" && task.Status == "completed") {
+			affected = append(affected, task)
+		}
+	}
+
+	if len(affected) > 0 {
+		fmt.Printf("%s %s %s\n", theme.Info(goalLabel(*goal)+":"), goal.Description, theme.Warn(fmt.Sprintf("has %d linked tasks:", len(affected))))
+		for _, task := range affected {
+			fmt.Printf("  %s %s %s\n", theme.Info(goalLabel(task)+":"), task.Description, theme.Unimportant("("+task.Status+")"))
+		}
+
+		if orphans == "" {
+			orphans = askOrphanAction(reader, action)
+		}
+		if !handleOrphans(reader, goalsManager, *goal, affected, action, orphans, target) {
+			return
+		}
+	}
+
+	if action == "delete" {
+		err = goalsManager.DeleteGoal(goal.UUID)
+	} else {
+		err = goalsManager.CompleteGoal(goal.UUID)
+	}
+	if err != nil {
+		color.Red("Error closing goal: %v", err)
+		return
+	}
+	if action == "delete" {
+		color.Green("Goal deleted successfully.")
+	} else {
+		color.Green("Goal marked as done.")
+	}
+}
+
+// askOrphanAction asks what to do with the tasks linked to a goal that is deleted or done
+func askOrphanAction(reader *bufio.Reader, action string) string {
+	options := "[r]elink to another goal/[u]nlink/[c]ascade delete/[A]bort"
+	if action == "done" {
+		options = "[K]eep links/[r]elink to another goal/[u]nlink/[c]ascade done/[a]bort"
+	}
+	fmt.Printf("%s %s: ", theme.Title("→"), options)
+	input, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "r":
+		return "relink"
+	case "u":
+		return "unlink"
+	case "c":
+		return "cascade"
+	case "k":
+		return "keep"
+	case "":
+		if action == "done" {
+			return "keep"
+		}
+	}
+	return "abort"
+}
+
+// handleOrphans relinks, unlinks or cascades the tasks of a goal and reports whether the goal may be closed
+func handleOrphans(reader *bufio.Reader, goalsManager *goals.Manager, goal types.Task, affected []types.Task, action, orphans, target string) bool {
+	switch orphans {
+	case "keep":
+		if action == "delete" {
+			color.Red("Deleted goals can not keep their links, use relink, unlink or cascade.")
+			return false
+		}
+		return true
+	case "relink":
+		replacement, ok := askGoal(reader, goalsManager, target, "Relink to goal ID")
+		if !ok {
+			return false
+		}
+		if replacement.UUID == goal.UUID {
+			color.Red("Can not relink tasks to the goal being closed.")
+			return false
+		}
+		return replaceLinks(goalsManager, goal, affected, replacement.UUID)
+	case "unlink":
+		return replaceLinks(goalsManager, goal, affected, "")
+	case "cascade":
+		if err := goalsManager.CascadeGoal(goal, action); err != nil {
+			color.Red("Error: %v", err)
+			return false
+		}
+		return true
+	case "abort":
+		color.Yellow("Aborted.")
+		return false
+	default:
+		color.Red("Unknown --orphans value %q, use relink, unlink, cascade or keep.", orphans)
+		return false
+	}
+}
+
+// replaceLinks moves the links of tasks from goal to replacement, or removes them if replacement is empty
+func replaceLinks(goalsManager *goals.Manager, goal types.Task, tasks []types.Task, replacement string) bool {
+	ok := true
+	for _, task := range tasks {
+		if err := goalsManager.ReplaceGoalLink(task, goal.UUID, replacement); err != nil {
+			color.Red("Error updating task %s: %v", goalLabel(task), err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// askGoal resolves the goal ID given by flag, or asks for one
+func askGoal(reader *bufio.Reader, goalsManager *goals.Manager, id, question string) (*types.Task, bool) {
+	if id == "" {
+		fmt.Printf("%s %s: ", theme.Title("→"), question)
+		input, _ := reader.ReadString('\n')
+		if id = strings.TrimSpace(input); id == "" {
+			color.Yellow("Aborted.")
+			return nil, false
+		}
+	}
+	id, err := resolveGoalArg(reader, goalsManager, id)
+	if err != nil {
+		color.Red("Error: %v", err)
+		return nil, false
+	}
+	goal, err := goalsManager.GetGoal(id)
+	if err != nil {
+		color.Red("Error: %v", err)
+		return nil, false
+	}
+	return goal, true
+}
+
+func runGoalsDoctor(cmd *cobra.Command, args []string) {
+	repairAll, _ := cmd.Flags().GetBool("yes")
+	target, _ := cmd.Flags().GetString("to")
+
+	goalsManager, err := getGoalsManager(cmd)
+	if err != nil {
+		color.Red("Error initializing goals manager: %v", err)
+		return
+	}
+
+	dangling, err := goalsManager.DanglingLinks()
+	if err != nil {
+		color.Red("Error checking goal links: %v", err)
+		return
+	}
+	if len(dangling) == 0 {
+		fmt.Println(theme.Success("✓ All goal links are valid."))
+		return
+	}
+
+	fmt.Println(theme.Warn(fmt.Sprintf("%d tasks link to goals that do not exist:", len(dangling))))
+	for _, link := range dangling {
+		fmt.Printf("  %s %s %s\n", theme.Info(goalLabel(link.Task)+":"), link.Task.Description, theme.Unimportant("→ "+strings.Join(link.Missing, ", ")))
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var replacement string
+	if target != "" {
+		goal, ok := askGoal(reader, goalsManager, target, "")
+		if !ok {
+			return
+		}
+		replacement = goal.UUID
+	}
+
+	interactive := false
+	if !repairAll {
+		fmt.Printf("\n%s Repair [a]ll/[i]nteractive/[N]one: ", theme.Title("→"))
+		input, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "a", "all":
+		case "i", "interactive":
+			interactive = true
+		default:
+			return
+		}
+	}
+
+	repaired := 0
+repair:
+	for i, link := range dangling {
+		linkTo := replacement
+		if interactive {
+			fmt.Printf("%s %s [u]nlink/[r]elink/[S]kip/[q]uit: ", theme.Title(fmt.Sprintf("(%d/%d)", i+1, len(dangling))), link.Task.Description)
+			input, _ := reader.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(input)) {
+			case "u":
+				linkTo = ""
+			case "r":
+				goal, ok := askGoal(reader, goalsManager, "", "Relink to goal ID")
+				if !ok {
+					continue
+				}
+				linkTo = goal.UUID
+			case "q":
+				break repair
+			default:
+				continue
+			}
+		}
+
+		if err := goalsManager.RepairLink(link, linkTo); err != nil {
+			color.Red("Error repairing task %s: %v", goalLabel(link.Task), err)
+			continue
+		}
+		repaired++
+	}
+	fmt.Println(theme.Success(fmt.Sprintf("✅ Repaired %d of %d tasks", repaired, len(dangling))))
+}
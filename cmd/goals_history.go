@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/taskvanguard/taskvanguard/internal/goals"
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
+	"github.com/taskvanguard/taskvanguard/internal/prompts"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+var goalsHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show achieved goals with completion date, duration and the tasks that got them there",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		includeDeleted, _ := cmd.Flags().GetBool("deleted")
+		brief, _ := cmd.Flags().GetBool("brief")
+
+		goalsManager, err := getGoalsManager(cmd)
+		if err != nil {
+			color.Red("Error initializing goals manager: %v", err)
+			return
+		}
+
+		history, err := goalsManager.History(includeDeleted)
+		if err != nil {
+			color.Red("Error getting goal history: %v", err)
+			return
+		}
+		if len(history) == 0 {
+			color.Yellow("No achieved goals yet.")
+			return
+		}
+
+		for _, entry := range history {
+			icon, status := "🏆", "achieved"
+			if !entry.Achieved {
+				icon, status = "✗", "abandoned"
+			}
+			fmt.Printf("\n%s %s %s\n", icon, theme.Title(entry.Goal.Description), theme.Unimportant(goalLabel(entry.Goal)))
+			fmt.Printf("   %s %s, after %d days, %d tasks\n", theme.Info(status+":"), entry.Ended.Local().Format("2006-01-02"), entry.Days(), len(entry.Tasks))
+			if brief {
+				continue
+			}
+			for _, task := range entry.Tasks {
+				done := ""
+				if task.End != nil {
+					done = task.End.Time().Local().Format("2006-01-02")
+				}
+				fmt.Printf("   %s %s %s\n", theme.Success("✓"), task.Description, theme.Unimportant(done))
+			}
+		}
+	},
+}
+
+func init() {
+	goalsHistoryCmd.Flags().Bool("deleted", false, "Include deleted goals as abandoned")
+	goalsHistoryCmd.Flags().Bool("brief", false, "Leave out the tasks of each goal")
+}
+
+// achievedGoals returns the achieved goals to send with prompts if goal_history_in_prompts is enabled, else nil
+func achievedGoals(cfg *types.Config, gate *privacy.Gate) []prompts.AchievedGoal {
+	if !cfg.Settings.GoalHistoryInPrompts {
+		return nil
+	}
+	userGoals, err := goals.NewManager(cfg).ListGoals()
+	if err != nil {
+		return nil
+	}
+	return prompts.ToAchievedGoals(gate.Goals(userGoals), cfg.Settings.GoalHistoryLimit)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/internal/goals"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// resolveGoalArg returns arg unchanged if it is a task ID or UUID, otherwise the UUID of the goal it names
+func resolveGoalArg(reader *bufio.Reader, goalsManager *goals.Manager, arg string) (string, error) {
+	if goals.IsTaskRef(arg) {
+		return arg, nil
+	}
+	goal, err := resolveGoal(reader, goalsManager, arg)
+	if err != nil {
+		return "", err
+	}
+	return goal.UUID, nil
+}
+
+// resolveGoalArgs resolves every goal name or query in args, see resolveGoalArg
+func resolveGoalArgs(goalsManager *goals.Manager, args []string) ([]string, error) {
+	reader := bufio.NewReader(os.Stdin)
+	ids := make([]string, len(args))
+	for i, arg := range args {
+		id, err := resolveGoalArg(reader, goalsManager, arg)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// resolveGoal finds the goal matching a name or fuzzy query and asks which one is meant if several match
+func resolveGoal(reader *bufio.Reader, goalsManager *goals.Manager, query string) (*types.Task, error) {
+	matches, err := goalsManager.FindGoals(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goals: %v", err)
+	}
+	return chooseGoal(reader, query, matches)
+}
+
+// chooseGoal returns the only goal in matches or asks which one is meant
+func chooseGoal(reader *bufio.Reader, query string, matches []types.Task) (*types.Task, error) {
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no goal matches %q", query)
+	case 1:
+		return &matches[0], nil
+	}
+
+	fmt.Printf("%s %q:\n", theme.Warn("Several goals match"), query)
+	for i, goal := range matches {
+		status := ""
+		if goal.Status != "pending" {
+			status = theme.Unimportant(" (" + goal.Status + ")")
+		}
+		fmt.Printf("  %d. %s %s%s\n", i+1, theme.Info(goalLabel(goal)+":"), goal.Description, status)
+	}
+
+	for {
+		fmt.Printf("%s Which goal? [1-%d]/[a]bort: ", theme.Title("→"), len(matches))
+		input, err := reader.ReadString('\n')
+		input = strings.ToLower(strings.TrimSpace(input))
+		if input == "a" || (input == "" && err != nil) {
+			return nil, fmt.Errorf("no goal selected for %q", query)
+		}
+		if n, convErr := strconv.Atoi(input); convErr == nil && n >= 1 && n <= len(matches) {
+			return &matches[n-1], nil
+		}
+	}
+}
+
+// resolveGoalAttributes replaces goal names in goal:<name> arguments of a task command with the goal UUIDs.
+// A goal named like the whole value wins, so names may contain commas or look like IDs. Otherwise
+// several goals are separated by commas and IDs become UUIDs, UUIDs are kept as they are.
+func resolveGoalAttributes(reader *bufio.Reader, goalsManager *goals.Manager, args []string) ([]string, error) {
+	var allGoals []types.Task
+	resolved := make([]string, len(args))
+	for i, arg := range args {
+		resolved[i] = arg
+		value, ok := strings.CutPrefix(arg, "goal:")
+		if !ok || value == "" {
+			continue
+		}
+
+		if allGoals == nil {
+			var err error
+			if allGoals, err = goalsManager.ListGoals(); err != nil {
+				return nil, fmt.Errorf("failed to get goals: %v", err)
+			}
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if named := goals.NamedGoals(allGoals, value); len(named) > 0 {
+			goal, err := chooseGoal(reader, value, named)
+			if err != nil {
+				return nil, fmt.Errorf("goal:%s: %v", value, err)
+			}
+			resolved[i] = "goal:" + goal.UUID
+			continue
+		}
+
+		var uuids []string
+		for _, name := range strings.Split(value, ",") {
+			name = strings.Trim(strings.TrimSpace(name), `"'`)
+			if name == "" {
+				continue
+			}
+			var goal *types.Task
+			var err error
+			if named := goals.NamedGoals(allGoals, name); len(named) > 0 {
+				goal, err = chooseGoal(reader, name, named)
+			} else if goals.IsTaskRef(name) {
+				// TaskWarrior stores the UDA as given, IDs change and must become UUIDs
+				goal, err = goalsManager.GetGoal(name)
+			} else {
+				goal, err = chooseGoal(reader, name, goals.MatchGoals(allGoals, name))
+			}
+			if err != nil {
+				return nil, fmt.Errorf("goal:%s: %v", name, err)
+			}
+			uuids = append(uuids, goal.UUID)
+		}
+		resolved[i] = "goal:" + strings.Join(uuids, ",")
+	}
+	return resolved, nil
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+	"github.com/taskvanguard/taskvanguard/internal/goals"
+	"github.com/taskvanguard/taskvanguard/internal/llm"
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
+	"github.com/taskvanguard/taskvanguard/internal/prompts"
+	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+)
+
+var goalsReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Walk through overdue, stale and unreviewed goals and plan next actions",
+	Long: `Shows every pending goal that is overdue, due for review, has no pending tasks or had no linked
+task completed in goal_stale_days. For each goal you can mark it as reviewed or let the LLM suggest
+next actions, which are linked to the goal when imported.`,
+	Run: runGoalsReview,
+}
+
+func init() {
+	addLLMFlags(goalsReviewCmd)
+}
+
+func runGoalsReview(cmd *cobra.Command, args []string) {
+	env, err := taskwarrior.Bootstrap(cmd)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		return
+	}
+	goalsManager := goals.NewManager(env.Config)
+
+	tree, err := goalsManager.GoalTree()
+	if err != nil {
+		fmt.Println(theme.Error(fmt.Sprintf("Error listing goals: %v", err)))
+		return
+	}
+
+	items := goals.ReviewItems(tree, env.Config.Settings, time.Now())
+	if len(items) == 0 {
+		fmt.Println(theme.Success("✓ All goals are on track."))
+		return
+	}
+
+	gate, err := privacy.NewGate(env.Config)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for i, item := range items {
+		goal := item.Node.Goal
+		fmt.Println(theme.Title("\n───────────────────────────────────────────────"))
+		fmt.Printf("%s %s %s\n", theme.Title(fmt.Sprintf("(%d/%d) 🏔️", i+1, len(items))), theme.Info(goalLabel(goal)+":"), goal.Description)
+		for _, reason := range item.Reasons {
+			fmt.Printf("  %s %s\n", theme.Warn("⚠"), reason)
+		}
+		p := item.Node.Progress
+		fmt.Printf("  %s %d done, %d pending, %d%%, projected: %s\n", theme.Info("Progress:"), p.Completed, p.Pending, p.Percent(), projectedDate(p))
+
+		for reviewing := true; reviewing; {
+			fmt.Printf("%s [n]ext actions via LLM/[r]eviewed/[S]kip/[q]uit: ", theme.Title("→"))
+			input, _ := reader.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(input)) {
+			case "n":
+				suggestNextActions(env, gate, item)
+			case "r":
+				if err := goalsManager.MarkReviewed(goal.UUID); err != nil {
+					fmt.Println(theme.Error(err.Error()))
+				} else {
+					fmt.Println(theme.Success("✓ Marked as reviewed"))
+				}
+				reviewing = false
+			case "q":
+				return
+			default:
+				reviewing = false
+			}
+		}
+	}
+}
+
+// suggestNextActions asks the LLM for the next tasks of a goal and offers to import them linked to the goal
+func suggestNextActions(env *taskwarrior.RuntimeContext, gate *privacy.Gate, item goals.ReviewItem) {
+	goal := item.Node.Goal
+	if err := gate.Check(goal.Project, goal.Tags); err != nil {
+		fmt.Println(theme.Warn("This goal is not sent to the LLM: " + err.Error()))
+		return
+	}
+
+	prompt, err := createNextActionsPrompt(env, gate, item)
+	if err != nil {
+		fmt.Printf("%s %s\n", theme.Error("❌ Failed to create prompt:"), err.Error())
+		return
+	}
+
+	s := spinner.New(spinner.CharSets[40], 100*time.Millisecond)
+	s.Prefix = "Planning next actions... "
+	s.Start()
+	response, err := gate.Send("goal_next_actions.md", prompt)
+	s.Stop()
+	if err != nil {
+		fmt.Printf("%s %s\n", theme.Error("❌ LLM error:"), err.Error())
+		return
+	}
+
+	var nextTasks []RoadmapTask
+	if err := json.Unmarshal([]byte(llm.CleanResponse(response)), &nextTasks); err != nil {
+		fmt.Printf("%s %s\n", theme.Error("❌ Failed to parse next actions:"), err.Error())
+		fmt.Printf("%s\n%s\n", theme.Warn("Raw response:"), response)
+		return
+	}
+
+	for _, task := range nextTasks {
+		details := []string{task.Priority}
+		if task.Estimate != "" {
+			details = append(details, task.Estimate)
+		}
+		fmt.Printf("  %d. %s %s %s %s\n", task.ID, task.Description, theme.Info(task.Project), theme.Success(formatTags(task.Tags)), theme.Unimportant("("+strings.Join(details, ", ")+")"))
+	}
+
+	if promptForTaskImport() {
+		if err := importTasksToTaskWarrior(env.Client, nextTasks, goal.UUID); err != nil {
+			fmt.Printf("%s %s\n", theme.Error("❌ Failed to import tasks:"), err.Error())
+		} else {
+			fmt.Println(theme.Success("✅ Tasks imported and linked to the goal"))
+		}
+	}
+}
+
+func createNextActionsPrompt(env *taskwarrior.RuntimeContext, gate *privacy.Gate, item goals.ReviewItem) (string, error) {
+	return prompts.RenderTemplate("goal_next_actions.md", nextActionsData(env, gate, item))
+}
+
+// nextActionsData returns what is sent to the LLM for the next actions of a goal
+func nextActionsData(env *taskwarrior.RuntimeContext, gate *privacy.Gate, item goals.ReviewItem) prompts.NextActionsData {
+	goal := item.Node.Goal
+	allowed, _ := gate.Partition(item.Node.Tasks)
+
+	var pending, completed []string
+	sort.Slice(allowed, func(i, j int) bool {
+		return allowed[i].End != nil && (allowed[j].End == nil || allowed[i].End.Time().After(allowed[j].End.Time()))
+	})
+	for _, task := range allowed {
+		if task.Status == "completed" {
+			if len(completed) < 10 {
+				completed = append(completed, gate.Text(task.Description))
+			}
+			continue
+		}
+		pending = append(pending, gate.Text(task.Description))
+	}
+
+	var userTags []string
+	for tagName := range env.Config.Tags {
+		if gate.AllowTag(tagName) {
+			userTags = append(userTags, tagName)
+		}
+	}
+	sort.Strings(userTags)
+
+	due := ""
+	if goal.Due != nil {
+		due = goal.Due.Time().Local().Format("2006-01-02")
+	}
+
+	return prompts.NextActionsData{
+		GoalDescription: gate.Text(goal.Description),
+		GoalDue:         due,
+		Reasons:         item.Reasons,
+		PendingTasks:    pending,
+		CompletedTasks:  completed,
+		UserTags:        userTags,
+		AchievedGoals:   achievedGoals(env.Config, gate),
+	}
+}
+
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "+" + strings.Join(tags, " +")
+}
@@ -2,12 +2,10 @@ package cmd
 
 import (
 	"bufio"
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,7 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"github.com/taskvanguard/taskvanguard/internal/goals"
-	"github.com/taskvanguard/taskvanguard/internal/llm"
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
 	"github.com/taskvanguard/taskvanguard/internal/prompts"
 	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
 	"github.com/taskvanguard/taskvanguard/pkg/theme"
@@ -31,12 +29,6 @@ type GuideResponse struct {
 	GoalName       string `json:"goal-name"`
 }
 
-// QuestionAnswer represents a single question-answer pair in the guide session.
-type QuestionAnswer struct {
-	Question string
-	Answer   string
-}
-
 // RoadmapTask represents a task in the generated roadmap.
 type RoadmapTask struct {
 	ID            int      `json:"id"`
@@ -52,42 +44,11 @@ type RoadmapTask struct {
 	DecisionPoint bool     `json:"decision_point"`
 }
 
-// TaskWarriorTask represents a task in TaskWarrior JSON format.
-type TaskWarriorTask struct {
-	UUID        string                   `json:"uuid"`
-	Status      string                   `json:"status"`
-	Entry       string                   `json:"entry"`
-	Description string                   `json:"description"`
-	Modified    string                   `json:"modified"`
-	Project     string                   `json:"project,omitempty"`
-	Tags        []string                 `json:"tags,omitempty"`
-	Priority    string                   `json:"priority,omitempty"`
-	Depends     string                   `json:"depends,omitempty"`
-	Goal        string                   `json:"goal,omitempty"`
-	Annotations []TaskWarriorAnnotation  `json:"annotations,omitempty"`
-}
-
-// TaskWarriorAnnotation represents an annotation in TaskWarrior format.
-type TaskWarriorAnnotation struct {
-	Entry       string `json:"entry"`
-	Description string `json:"description"`
-}
-
-type GuideQuestionData struct {
-	QAHistory         string
-	QuestionThreshold int
-	QuestionCount     int
-}
-
-type GuideSummaryData struct {
-	QAHistory string
-}
-
-type GuideRoadmapData struct {
-	GoalSummary    string
-	AnswersSummary string
-	UserTags       string
-}
+// The questions every guide session starts with
+const (
+	guideGoalQuestion      = "What is a specific goal you want to achieve?"
+	guideTimeframeQuestion = "What is a realistic timeframe for achieving this goal?"
+)
 
 var guideCmd = &cobra.Command{
 	Use:   "guide",
@@ -116,6 +77,13 @@ func runGuide(cmd *cobra.Command, args []string) {
 	}
 	questionsCount += 2
 
+	// One gate for the whole session keeps redaction placeholders stable across prompts
+	gate, err := privacy.NewGate(env.Config)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		return
+	}
+
 	goal := promptForGoal(questionsCount)
 	if goal == "" {
 		fmt.Println(theme.Warn("No goal provided. Exiting."))
@@ -128,15 +96,15 @@ func runGuide(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	qaHistory := []QuestionAnswer{{
-		Question: "What is a specific goal you want to achieve?",
+	qaHistory := []prompts.QuestionAnswer{{
+		Question: guideGoalQuestion,
 		Answer:   goal,
 	}, {
-		Question: "What is a realistic timeframe for achieving this goal?",
+		Question: guideTimeframeQuestion,
 		Answer:   timeframe,
 	}}
 
-	guideResult, err := conductQuestioningSession(env.Config, qaHistory, questionsCount)
+	guideResult, err := conductQuestioningSession(env.Config, gate, qaHistory, questionsCount)
 	if err != nil {
 		fmt.Println(theme.Error(err.Error()))
 		return
@@ -147,7 +115,7 @@ func runGuide(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	generateRoadmap(env.Config, guideResult)
+	generateRoadmap(env.Client, env.Config, gate, guideResult)
 }
 
 func promptForGoal(totalQuestions int) string {
@@ -175,52 +143,27 @@ func promptForTimeframe(totalQuestions int) string {
 	return strings.TrimSpace(timeframe)
 }
 
-func conductQuestioningSession(cfg *types.Config, qaHistory []QuestionAnswer, maxQuestions int) (*GuideResponse, error) {
-	llmClient, err := llm.NewClient(&cfg.LLM)
-	if err != nil {
-		return nil, fmt.Errorf("init llm client: %w", err)
-	}
-
+func conductQuestioningSession(cfg *types.Config, gate *privacy.Gate, qaHistory []prompts.QuestionAnswer, maxQuestions int) (*GuideResponse, error) {
 	questionCount := 1
 	
 	for questionCount < maxQuestions {
 		currentQuestionCount := len(qaHistory)
-		prompt, err := createQuestionPrompt(qaHistory, maxQuestions, currentQuestionCount)
+		prompt, err := createQuestionPrompt(gate, qaHistory, maxQuestions, currentQuestionCount)
 		if err != nil {
 			return nil, fmt.Errorf("create prompt: %w", err)
 		}
 
-		if cfg.Settings.Debug {
-			fmt.Println(theme.Title("LLM Question Prompt"))
-			fmt.Println(theme.Info(prompt))
-		}
-
 		s := spinner.New(spinner.CharSets[40], 100*time.Millisecond)
 		s.Prefix = "Thinking... "
 		s.Start()
 
-		messages := []llm.Message{{
-			Role:    "user",
-			Content: prompt,
-		}}
-
-		if !cfg.Settings.EnableLLM {
-			s.Stop()
-			return nil, fmt.Errorf("sending API Request to LLM is disabled via config")
-		}
-
-		response, err := llmClient.Chat(messages)
+		response, err := gate.Send("guide_questions.md", prompt)
 		s.Stop()
 		
 		if err != nil {
 			return nil, fmt.Errorf("llm chat error: %w", err)
 		}
 
-		if cfg.Settings.Debug {
-			fmt.Println(theme.Title("LLM Question Response"))
-			fmt.Println(theme.Info(response))
-		}
-
 		var questionResp struct {
 			Question string `json:"question"`
 		}
@@ -242,44 +185,29 @@ func conductQuestioningSession(cfg *types.Config, qaHistory []QuestionAnswer, ma
 		}
 		answer = strings.TrimSpace(answer)
 
-		qaHistory = append(qaHistory, QuestionAnswer{
+		qaHistory = append(qaHistory, prompts.QuestionAnswer{
 			Question: questionResp.Question,
 			Answer:   answer,
 		})
 	}
 
 	// Now use the summary template
-	prompt, err := createSummaryPrompt(qaHistory)
+	prompt, err := createSummaryPrompt(gate, qaHistory)
 	if err != nil {
 		return nil, fmt.Errorf("create summary prompt: %w", err)
 	}
 
-	if cfg.Settings.Debug {
-		fmt.Println(theme.Title("LLM Summary Prompt"))
-		fmt.Println(theme.Info(prompt))
-	}
-
 	s := spinner.New(spinner.CharSets[40], 100*time.Millisecond)
 	s.Prefix = "Summarizing... "
 	s.Start()
 
-	messages := []llm.Message{{
-		Role:    "user",
-		Content: prompt,
-	}}
-
-	response, err := llmClient.Chat(messages)
+	response, err := gate.Send("guide_summary.md", prompt)
 	s.Stop()
 	
 	if err != nil {
 		return nil, fmt.Errorf("summary llm chat error: %w", err)
 	}
 
-	if cfg.Settings.Debug {
-		fmt.Println(theme.Title("LLM Summary Response"))
-		fmt.Println(theme.Info(response))
-	}
-
 	var finalResp GuideResponse
 	if err := json.Unmarshal([]byte(response), &finalResp); err != nil {
 		return nil, fmt.Errorf("unmarshall summary llm response: %w", err)
@@ -288,50 +216,27 @@ func conductQuestioningSession(cfg *types.Config, qaHistory []QuestionAnswer, ma
 	return &finalResp, nil
 }
 
-func createQuestionPrompt(qaHistory []QuestionAnswer, maxQuestions int, currentQuestionCount int) (string, error) {
-	var builder strings.Builder
-	for i, qa := range qaHistory {
-		builder.WriteString(fmt.Sprintf("Q%d: %s\nA%d: %s\n\n", i+1, qa.Question, i+1, qa.Answer))
-	}
-	qaHistoryStr := builder.String()
-
-	questionData := GuideQuestionData{
-		QAHistory:         qaHistoryStr,
+func createQuestionPrompt(gate *privacy.Gate, qaHistory []prompts.QuestionAnswer, maxQuestions int, currentQuestionCount int) (string, error) {
+	return prompts.RenderTemplate("guide_questions.md", prompts.GuideQuestionData{
+		QAHistory:         gatedAnswers(gate, qaHistory),
 		QuestionThreshold: maxQuestions,
 		QuestionCount:     currentQuestionCount,
-	}
-
-	template, err := prompts.LoadPrompt("guide_questions.md")
-	if err != nil {
-		return "", err
-	}
-
-	prompt := strings.ReplaceAll(template, "{{ .QAHistory }}", questionData.QAHistory)
-	prompt = strings.ReplaceAll(prompt, "{{ .QuestionThreshold }}", fmt.Sprintf("%d", questionData.QuestionThreshold))
-	prompt = strings.ReplaceAll(prompt, "{{ .QuestionCount }}", fmt.Sprintf("%d", questionData.QuestionCount))
-
-	return prompt, nil
+	})
 }
 
-func createSummaryPrompt(qaHistory []QuestionAnswer) (string, error) {
-	var builder strings.Builder
-	for i, qa := range qaHistory {
-		builder.WriteString(fmt.Sprintf("Q%d: %s\nA%d: %s\n\n", i+1, qa.Question, i+1, qa.Answer))
-	}
-	qaHistoryStr := builder.String()
-
-	summaryData := GuideSummaryData{
-		QAHistory: qaHistoryStr,
-	}
+func createSummaryPrompt(gate *privacy.Gate, qaHistory []prompts.QuestionAnswer) (string, error) {
+	return prompts.RenderTemplate("guide_summary.md", prompts.GuideSummaryData{
+		QAHistory: gatedAnswers(gate, qaHistory),
+	})
+}
 
-	template, err := prompts.LoadPrompt("guide_summary.md")
-	if err != nil {
-		return "", err
+// gatedAnswers passes the answers of the session through the privacy gate, the questions come from the LLM
+func gatedAnswers(gate *privacy.Gate, qaHistory []prompts.QuestionAnswer) []prompts.QuestionAnswer {
+	gated := make([]prompts.QuestionAnswer, 0, len(qaHistory))
+	for _, qa := range qaHistory {
+		gated = append(gated, prompts.QuestionAnswer{Question: qa.Question, Answer: gate.Text(qa.Answer)})
 	}
-
-	prompt := strings.ReplaceAll(template, "{{ .QAHistory }}", summaryData.QAHistory)
-
-	return prompt, nil
+	return gated
 }
 
 func confirmGoal(guideResult *GuideResponse) bool {
@@ -365,27 +270,28 @@ func confirmGoal(guideResult *GuideResponse) bool {
 	}
 }
 
-func createRoadmapPrompt(cfg *types.Config, guideResult *GuideResponse) (string, error) {
+func createRoadmapPrompt(cfg *types.Config, gate *privacy.Gate, guideResult *GuideResponse) (string, error) {
+	return prompts.RenderTemplate("guide_roadmap.md", roadmapData(cfg, gate, guideResult))
+}
+
+// roadmapData returns what is sent to the LLM for the roadmap of a guide session
+func roadmapData(cfg *types.Config, gate *privacy.Gate, guideResult *GuideResponse) prompts.GuideRoadmapData {
 	// get user tags from config
 	var userTags []string
 	for tagName := range cfg.Tags {
+		if !gate.AllowTag(tagName) {
+			continue
+		}
 		userTags = append(userTags, tagName)
 	}
-	userTagsStr := strings.Join(userTags, ", ")
-	if userTagsStr == "" {
-		userTagsStr = "key, sb, fast, cut, ai (use appropriate tags based on task characteristics)"
-	}
+	sort.Strings(userTags)
 
-	template, err := prompts.LoadPrompt("guide_roadmap.md")
-	if err != nil {
-		return "", err
+	return prompts.GuideRoadmapData{
+		GoalSummary:    gate.Text(guideResult.GoalSummary),
+		AnswersSummary: gate.Text(guideResult.AnswersSummary),
+		UserTags:       userTags,
+		AchievedGoals:  achievedGoals(cfg, gate),
 	}
-
-	prompt := strings.ReplaceAll(template, "{{ .GoalSummary }}", guideResult.GoalSummary)
-	prompt = strings.ReplaceAll(prompt, "{{ .AnswersSummary }}", guideResult.AnswersSummary)
-	prompt = strings.ReplaceAll(prompt, "{{ .UserTags }}", userTagsStr)
-
-	return prompt, nil
 }
 
 // createGoalFromGuideResult creates a goal in TaskWarrior based on the guide result
@@ -430,7 +336,7 @@ func runAnalyzeCommand(cfg *types.Config, goalUUID string) error {
 	
 	// Create a new cobra command context for analyze
 	// This is cleaner than modifying global state
-	analyzeArgs := []string{fmt.Sprintf("goal:%s", goalUUID)}
+	analyzeArgs := []string{fmt.Sprintf("goal.has:%s", goalUUID)}
 	
 	// Execute the analyze command by calling its Run function directly
 	analyzeCmd.Run(analyzeCmd, analyzeArgs)
@@ -439,7 +345,7 @@ func runAnalyzeCommand(cfg *types.Config, goalUUID string) error {
 }
 
 // generateRoadmap creates a roadmap from the guide result and displays it.
-func generateRoadmap(cfg *types.Config, guideResult *GuideResponse) {
+func generateRoadmap(client *taskwarrior.Client, cfg *types.Config, gate *privacy.Gate, guideResult *GuideResponse) {
 	fmt.Println(theme.Title("\n───────────────────────────────────────────────"))
 	fmt.Println(theme.Title("          🗺️  ROADMAP GENERATION:"))
 	fmt.Println(theme.Title("───────────────────────────────────────────────"))
@@ -453,15 +359,14 @@ func generateRoadmap(cfg *types.Config, guideResult *GuideResponse) {
 	
 	fmt.Printf("%s Goal created (ID: %d, UUID: %s)\n", theme.Success("✅"), goalID, goalUUID)
 	
-	llmClient, err := llm.NewClient(&cfg.LLM)
+	prompt, err := createRoadmapPrompt(cfg, gate, guideResult)
 	if err != nil {
-		fmt.Printf("%s %s\n", theme.Error("❌ Failed to initialize LLM client:"), err.Error())
+		fmt.Printf("%s %s\n", theme.Error("❌ Failed to create roadmap prompt:"), err.Error())
 		return
 	}
 
-	prompt, err := createRoadmapPrompt(cfg, guideResult)
-	if err != nil {
-		fmt.Printf("%s %s\n", theme.Error("❌ Failed to create roadmap prompt:"), err.Error())
+	if !cfg.Settings.EnableLLM {
+		fmt.Printf("%s %s\n", theme.Error("❌ LLM disabled:"), "Enable LLM in config to generate roadmap")
 		return
 	}
 
@@ -469,23 +374,7 @@ func generateRoadmap(cfg *types.Config, guideResult *GuideResponse) {
 	s.Prefix = "Creating roadmap... "
 	s.Start()
 
-	messages := []llm.Message{{
-		Role:    "user",
-		Content: prompt,
-	}}
-
-	if cfg.Settings.Debug {
-		fmt.Println(theme.Title("LLM Roadmap Prompt"))
-		fmt.Println(theme.Info(prompt))
-	}
-
-	if !cfg.Settings.EnableLLM {
-		s.Stop()
-		fmt.Printf("%s %s\n", theme.Error("❌ LLM disabled:"), "Enable LLM in config to generate roadmap")
-		return
-	}
-
-	response, err := llmClient.Chat(messages)
+	response, err := gate.Send("guide_roadmap.md", prompt)
 	s.Stop()
 	
 	if err != nil {
@@ -493,11 +382,6 @@ func generateRoadmap(cfg *types.Config, guideResult *GuideResponse) {
 		return
 	}
 
-	if cfg.Settings.Debug {
-		fmt.Println(theme.Title("LLM Roadmap Response"))
-		fmt.Println(theme.Info(response))
-	}
-
 	var roadmapTasks []RoadmapTask
 	if err := json.Unmarshal([]byte(response), &roadmapTasks); err != nil {
 		fmt.Printf("%s %s\n", theme.Error("❌ Failed to parse roadmap:"), err.Error())
@@ -523,7 +407,7 @@ func generateRoadmap(cfg *types.Config, guideResult *GuideResponse) {
 	fmt.Printf("%s %s\n", theme.Warn("↪️ Next Steps:"), "Tasks are ready for import into TaskWarrior")
 
 	if promptForTaskImport() {
-		if err := importTasksToTaskWarrior(roadmapTasks, goalUUID); err != nil {
+		if err := importTasksToTaskWarrior(client, roadmapTasks, goalUUID); err != nil {
 			fmt.Printf("%s %s\n", theme.Error("❌ Failed to import tasks:"), err.Error())
 		} else {
 			fmt.Printf("%s %s\n", theme.Success("✅ Tasks imported successfully!"), "")
@@ -533,10 +417,10 @@ func generateRoadmap(cfg *types.Config, guideResult *GuideResponse) {
 				fmt.Printf("%s Running analyze for goal tasks...\n", theme.Info("🚀"))
 				if err := runAnalyzeCommand(cfg, goalUUID); err != nil {
 					fmt.Printf("%s %s\n", theme.Error("❌ Failed to run analyze:"), err.Error())
-					fmt.Printf("%s %s\n", theme.Info("💡 Manual command:"), fmt.Sprintf("vanguard analyze goal:%s", goalUUID))
+					fmt.Printf("%s %s\n", theme.Info("💡 Manual command:"), fmt.Sprintf("vanguard analyze goal.has:%s", goalUUID))
 				}
 			} else {
-				fmt.Printf("%s %s\n", theme.Info("💡 Manual command:"), fmt.Sprintf("vanguard analyze goal:%s", goalUUID))
+				fmt.Printf("%s %s\n", theme.Info("💡 Manual command:"), fmt.Sprintf("vanguard analyze goal.has:%s", goalUUID))
 			}
 		}
 	}
@@ -737,10 +621,10 @@ func promptForTaskImport() bool {
 	return response == "y" || response == "yes" || response == ""
 }
 
-func convertToTaskWarriorFormat(roadmapTasks []RoadmapTask, goalUUID string) ([]TaskWarriorTask, map[string]string, error) {
-	var twTasks []TaskWarriorTask
+func convertToTaskWarriorFormat(compat *taskwarrior.Compat, roadmapTasks []RoadmapTask, goalUUID string) ([]types.Task, map[string]string, error) {
+	var twTasks []types.Task
 	idToUUID := make(map[string]string)
-	now := time.Now().UTC().Format("20060102T150405Z")
+	now := time.Now().UTC().Truncate(time.Second)
 	
 	// First pass: create UUIDs and basic tasks
 	for _, task := range roadmapTasks {
@@ -759,48 +643,46 @@ func convertToTaskWarriorFormat(roadmapTasks []RoadmapTask, goalUUID string) ([]
 		}
 		
 		// Create annotations for custom fields
-		var annotations []TaskWarriorAnnotation
+		var notes []string
 		
 		if task.Estimate != "" {
-			annotations = append(annotations, TaskWarriorAnnotation{
-				Entry:       now,
-				Description: "Estimate: " + task.Estimate,
-			})
+			notes = append(notes, "Estimate: "+task.Estimate)
 		}
 		
 		if len(task.Resources) > 0 {
-			annotations = append(annotations, TaskWarriorAnnotation{
-				Entry:       now,
-				Description: "Resources: " + strings.Join(task.Resources, ", "),
-			})
+			notes = append(notes, "Resources: "+strings.Join(task.Resources, ", "))
 		}
 		
 		if task.Risks != "" {
-			annotations = append(annotations, TaskWarriorAnnotation{
-				Entry:       now,
-				Description: "Risks: " + task.Risks,
-			})
+			notes = append(notes, "Risks: "+task.Risks)
 		}
 		
 		if task.Metrics != "" {
-			annotations = append(annotations, TaskWarriorAnnotation{
-				Entry:       now,
-				Description: "Success: " + task.Metrics,
-			})
+			notes = append(notes, "Success: "+task.Metrics)
 		}
 		
 		if task.DecisionPoint {
-			annotations = append(annotations, TaskWarriorAnnotation{
-				Entry:       now,
-				Description: "Decision Point: Review and adapt here",
+			notes = append(notes, "Decision Point: Review and adapt here")
+		}
+
+		var annotations []types.Annotation
+		for i, note := range notes {
+			entry := now
+			// TaskChampion keys annotations by entry time, identical timestamps would overwrite each other
+			if compat.UniqueAnnotationEntries {
+				entry = now.Add(time.Duration(i) * time.Second)
+			}
+			annotations = append(annotations, types.Annotation{
+				Entry:       types.TWTime(entry),
+				Description: note,
 			})
 		}
 		
-		twTask := TaskWarriorTask{
+		twTask := types.Task{
 			UUID:        taskUUID,
 			Status:      "pending",
-			Entry:       now,
-			Modified:    now,
+			Entry:       types.TWTime(now),
+			Modified:    types.TWTime(now),
 			Description: task.Description,
 			Project:     task.Project,
 			Tags:        task.Tags,
@@ -821,66 +703,29 @@ func convertToTaskWarriorFormat(roadmapTasks []RoadmapTask, goalUUID string) ([]
 					dependUUIDs = append(dependUUIDs, depUUID)
 				}
 			}
-			if len(dependUUIDs) > 0 {
-				// TaskWarrior spec: comma-separated UUIDs without spaces
-				twTasks[i].Depends = strings.Join(dependUUIDs, ",")
-			}
+			twTasks[i].Depends = dependUUIDs
 		}
 	}
 	
 	return twTasks, idToUUID, nil
 }
 
-func importTasksToTaskWarrior(roadmapTasks []RoadmapTask, goalUUID string) error {
-	twTasks, _, err := convertToTaskWarriorFormat(roadmapTasks, goalUUID)
+func importTasksToTaskWarrior(client *taskwarrior.Client, roadmapTasks []RoadmapTask, goalUUID string) error {
+	compat, err := client.Compat()
 	if err != nil {
-		return fmt.Errorf("failed to convert tasks: %w", err)
+		return err
 	}
-	
-	// Create temporary file
-	tempDir, err := os.MkdirTemp("", "taskvanguard-import-")
-	if err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
-	}
-	defer os.RemoveAll(tempDir)
-	
-	tempFile := filepath.Join(tempDir, "tasks.json")
-	file, err := os.Create(tempFile)
+
+	twTasks, _, err := convertToTaskWarriorFormat(compat, roadmapTasks, goalUUID)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer file.Close()
-	
-	// Write each task as a separate JSON line according to TaskWarrior spec
-	for _, task := range twTasks {
-		taskJSON, err := json.Marshal(task)
-		if err != nil {
-			return fmt.Errorf("failed to marshal task: %w", err)
-		}
-		
-		// TaskWarrior import format: one JSON object per line, no pretty printing
-		if _, err := file.WriteString(string(taskJSON) + "\n"); err != nil {
-			return fmt.Errorf("failed to write task to file: %w", err)
-		}
+		return fmt.Errorf("failed to convert tasks: %w", err)
 	}
 	
-	file.Close()
-	
-	// Import using task import command with context timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-	
 	s := spinner.New(spinner.CharSets[40], 100*time.Millisecond)
 	s.Prefix = "Importing tasks... "
 	s.Start()
 	defer s.Stop()
 	
-	cmd := exec.CommandContext(ctx, "task", "import", tempFile)
-	output, err := cmd.CombinedOutput()
-	
-	if err != nil {
-		return fmt.Errorf("task import failed: %w\nOutput: %s", err, string(output))
-	}
-	
-	return nil
+	_, err = client.ImportTasks(twTasks)
+	return err
 }
@@ -130,7 +130,7 @@ func setupConfiguration(configPath string) {
 
 	// Create config directory
 	configDir := filepath.Dir(configPath)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(configDir, 0700); err != nil {
 		fmt.Printf(theme.Error("Failed to create config directory: %v"), err)
 		return
 	}
@@ -208,8 +208,12 @@ func setupConfiguration(configPath string) {
 	// 	return
 	// }
 
-	fmt.Println(theme.Warn("Go here and put your API Credentials in:"))
-	fmt.Println(theme.Info(configPath))
+	credentialsPath, _ := config.CredentialsPath()
+	fmt.Println(theme.Warn("Provide your API key in one of these ways:"))
+	fmt.Println(theme.Info("  export " + config.APIKeyEnv + "=<key>"))
+	fmt.Println(theme.Info("  api_key_cmd: \"pass show openai\" in " + configPath))
+	fmt.Println(theme.Info("  default: <key> in " + credentialsPath + " (chmod 600)"))
+	fmt.Println(theme.Info("  api_key: <key> in " + configPath))
 
 	fmt.Println("")
 	fmt.Println("Press Enter to continue...")
@@ -240,7 +244,7 @@ func setupTaskBackup() {
 			return
 		}
 
-		if err := os.WriteFile(backupPath, output, 0644); err != nil {
+		if err := os.WriteFile(backupPath, output, 0600); err != nil {
 			fmt.Printf(theme.Error("Failed to save backup: %v"), err)
 			return
 		}
@@ -321,8 +325,8 @@ func setupGoalTracking(twConfigPath string) {
 	if response == "y" || response == "Y" {
 		fmt.Println("")
 		fmt.Println(theme.Info("Goal tracking enables:"))
-		fmt.Println("  • Create goals as tasks in project:goals")
-		fmt.Println("  • Link other tasks to goals via goal:\"name\" in task commands")
+		fmt.Println("  • Create goals as tasks in project:goals (or tagged +goal, see goal_mode in the config)")
+		fmt.Println("  • Link other tasks to goals via goal:\"name\" in vanguard add, or vanguard goals link <task> \"name\"")
 		fmt.Println("  • Auto-assign new tasks to goals")
 		fmt.Println("")
 
@@ -335,6 +339,12 @@ func setupGoalTracking(twConfigPath string) {
 		fmt.Println(theme.Success("uda.skipped.label=Skipped"))
 		fmt.Println(theme.Success("uda.skipped.type=numeric"))
 		fmt.Println(theme.Success("uda.skipped.values=0"))
+		fmt.Println(theme.Success("uda.review.label=Review every"))
+		fmt.Println(theme.Success("uda.review.type=numeric"))
+		fmt.Println(theme.Success("uda.reviewed.label=Reviewed"))
+		fmt.Println(theme.Success("uda.reviewed.type=date"))
+		fmt.Println(theme.Success("uda.estimate.label=Estimate"))
+		fmt.Println(theme.Success("uda.estimate.type=numeric"))
 		fmt.Println("")
 
 		fmt.Print(theme.Title("Would you like me to add these to your .taskrc automatically? ") + theme.Info("y/n") + ": ")
@@ -395,7 +405,7 @@ func addUDAsToTaskrc(twConfigPath string) error {
 			return fmt.Errorf("failed to read .taskrc for backup: %v", err)
 		}
 		
-		if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		if err := os.WriteFile(backupPath, data, 0600); err != nil {
 			return fmt.Errorf("failed to create .taskrc backup: %v", err)
 		}
 		
@@ -419,6 +429,12 @@ func addUDAsToTaskrc(twConfigPath string) error {
 		"uda.skipped.label=Skipped",
 		"uda.skipped.type=numeric",
 		"uda.skipped.values=0",
+		"uda.review.label=Review every",
+		"uda.review.type=numeric",
+		"uda.reviewed.label=Reviewed",
+		"uda.reviewed.type=date",
+		"uda.estimate.label=Estimate",
+		"uda.estimate.type=numeric",
 		"# TaskVanguard End #",
 	}
 
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taskvanguard/taskvanguard/internal/analyzer"
+	"github.com/taskvanguard/taskvanguard/internal/goals"
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
+	"github.com/taskvanguard/taskvanguard/internal/prompts"
+	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+)
+
+var privacyCmd = &cobra.Command{
+	Use:   "privacy",
+	Short: "Inspect what is sent to the LLM",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var privacyAuditCmd = &cobra.Command{
+	Use:   "audit <analyze|spot|add|guide|goals align|goals review> [arguments...]",
+	Short: "Show which tasks and fields a command would send to the LLM",
+	Long: `Runs the same task selection and privacy gate as the given command without contacting the LLM
+and prints the exact, redacted payload together with every task that is held back and why.
+
+  vanguard privacy audit analyze project:work
+  vanguard privacy audit spot
+  vanguard privacy audit add "call bob@example.com" project:pers
+  vanguard privacy audit guide "run a marathon, ask coach@example.com"
+  vanguard privacy audit goals align project:work
+  vanguard privacy audit goals review`,
+	Args:      cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeAuditArgs,
+	Run:               runPrivacyAudit,
+}
+
+func init() {
+	privacyCmd.AddCommand(privacyAuditCmd)
+}
+
+func runPrivacyAudit(cmd *cobra.Command, args []string) {
+	env, err := taskwarrior.Bootstrap(cmd)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		return
+	}
+
+	gate, err := privacy.NewGate(env.Config)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		return
+	}
+
+	command, taskArgs := args[0], args[1:]
+	if command == "goals" && len(taskArgs) > 0 {
+		command, taskArgs = "goals "+taskArgs[0], taskArgs[1:]
+	}
+	switch command {
+	case "analyze":
+		err = auditAnalyze(env, gate, taskArgs)
+	case "spot":
+		err = auditSpot(env, gate, taskArgs)
+	case "add":
+		err = auditAdd(env, gate, taskArgs)
+	case "guide":
+		err = auditGuide(env, gate, taskArgs)
+	case "goals align":
+		err = auditGoalsAlign(env, gate, taskArgs)
+	case "goals review":
+		err = auditGoalsReview(env, gate)
+	default:
+		err = fmt.Errorf("unknown command %q, expected analyze, spot, add, guide, goals align or goals review", command)
+	}
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+	}
+}
+
+func auditAnalyze(env *taskwarrior.RuntimeContext, gate *privacy.Gate, args []string) error {
+	tasks, blocked, err := fetchAnalyzeTasks(env, args)
+	if err != nil {
+		return err
+	}
+	if limit := env.Config.Settings.TaskImportLimit; len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	payload := make([]prompts.Task, 0, len(tasks))
+	for _, task := range tasks {
+		promptTask, err := analyzer.PromptTask(gate, taskToArgs(task))
+		if err != nil {
+			return err
+		}
+		payload = append(payload, promptTask)
+	}
+
+	printAuditBlocked(blocked)
+	printAuditPayload(fmt.Sprintf("Tasks sent (%d)", len(payload)), payload)
+	printAuditContext(env, gate)
+	return nil
+}
+
+func auditSpot(env *taskwarrior.RuntimeContext, gate *privacy.Gate, args []string) error {
+	tasks, blocked, err := spotlightCandidates(env.Client, env.Config, gate, args)
+	if err != nil {
+		return err
+	}
+
+	printAuditBlocked(blocked)
+	printAuditPayload(fmt.Sprintf("Tasks sent (%d most urgent)", len(tasks)), spotlightViews(env.Config, gate, tasks))
+	printAuditPayload("Recent completions sent", recentCompletions(env.Client, gate))
+	return nil
+}
+
+// auditGuide shows how the answers of a guide session are redacted and what is sent with the roadmap prompt
+func auditGuide(env *taskwarrior.RuntimeContext, gate *privacy.Gate, args []string) error {
+	if len(args) > 0 {
+		printAuditPayload("Answer sent", gatedAnswers(gate, []prompts.QuestionAnswer{{
+			Question: guideGoalQuestion,
+			Answer:   strings.Join(args, " "),
+		}}))
+	} else {
+		fmt.Println(theme.Unimportant("\nPass an answer as arguments to see how it is redacted."))
+	}
+
+	roadmap := roadmapData(env.Config, gate, &GuideResponse{})
+	printAuditPayload("Sent with the roadmap prompt", map[string]any{
+		"user_tags":      roadmap.UserTags,
+		"achieved_goals": roadmap.AchievedGoals,
+	})
+	fmt.Println(theme.Unimportant("\nThe roadmap prompt also holds the goal and answer summaries the LLM wrote from your redacted answers."))
+	return nil
+}
+
+func auditGoalsAlign(env *taskwarrior.RuntimeContext, gate *privacy.Gate, args []string) error {
+	tree, err := goals.NewManager(env.Config).GoalTree()
+	if err != nil {
+		return err
+	}
+	tasks, blocked, err := alignCandidates(env, gate, args)
+	if err != nil {
+		return err
+	}
+	if limit := env.Config.Settings.TaskImportLimit; len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	payload := analyzer.AlignmentPayload(gate, tasks, goalsOf(pendingGoalNodes(tree)))
+	printAuditBlocked(blocked)
+	printAuditPayload(fmt.Sprintf("Goals sent (%d)", len(payload.Goals)), payload.Goals)
+	printAuditPayload(fmt.Sprintf("Tasks sent (%d)", len(payload.Tasks)), payload.Tasks)
+	return nil
+}
+
+func auditGoalsReview(env *taskwarrior.RuntimeContext, gate *privacy.Gate) error {
+	tree, err := goals.NewManager(env.Config).GoalTree()
+	if err != nil {
+		return err
+	}
+
+	items := goals.ReviewItems(tree, env.Config.Settings, time.Now())
+	if len(items) == 0 {
+		fmt.Println(theme.Success("✓ No goal needs a review, nothing would be sent."))
+		return nil
+	}
+	for _, item := range items {
+		goal := item.Node.Goal
+		if err := gate.Check(goal.Project, goal.Tags); err != nil {
+			fmt.Println(theme.Title(fmt.Sprintf("\nGoal %s held back:", goalLabel(goal))))
+			fmt.Printf("  %s\n", theme.Unimportant(err.Error()))
+			continue
+		}
+		printAuditPayload(fmt.Sprintf("Sent for next actions of goal %s", goalLabel(goal)), nextActionsData(env, gate, item))
+	}
+	return nil
+}
+
+func auditAdd(env *taskwarrior.RuntimeContext, gate *privacy.Gate, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("please provide the task arguments you would pass to 'vanguard add'")
+	}
+
+	promptTask, err := analyzer.PromptTask(gate, strings.Join(args, " "))
+	if err != nil {
+		fmt.Println(theme.Title("\nNothing sent, task is blocked:"))
+		fmt.Printf("  %s\n", theme.Warn(err.Error()))
+		return nil
+	}
+
+	printAuditPayload("Task sent", promptTask)
+	printAuditContext(env, gate)
+	return nil
+}
+
+func printAuditBlocked(blocked []privacy.Blocked) {
+	fmt.Println(theme.Title(fmt.Sprintf("\nTasks held back (%d):", len(blocked))))
+	for _, b := range blocked {
+		fmt.Printf("  %d: %s\n", b.Task.ID, b.Task.Description)
+		fmt.Printf("     %s\n", theme.Unimportant(b.Reason))
+	}
+}
+
+func printAuditPayload(title string, payload interface{}) {
+	out, err := json.MarshalIndent(payload, "  ", "  ")
+	if err != nil {
+		out = []byte(err.Error())
+	}
+	fmt.Println(theme.Title("\n" + title + ":"))
+	fmt.Printf("  %s\n", out)
+}
+
+// printAuditContext shows the goals, projects and tags that are sent along with analyze and add prompts
+func printAuditContext(env *taskwarrior.RuntimeContext, gate *privacy.Gate) {
+	fmt.Println(theme.Title("\nGoals sent:"))
+	for _, goal := range gate.Goals(env.UserGoals) {
+		if goal.Status != "completed" {
+			fmt.Printf("  - %s\n", goal.Description)
+		}
+	}
+	if env.Config.Settings.GoalHistoryInPrompts {
+		fmt.Println(theme.Title("\nAchieved goals sent:"))
+		for _, goal := range prompts.ToAchievedGoals(gate.Goals(env.UserGoals), env.Config.Settings.GoalHistoryLimit) {
+			fmt.Printf("  - %s (achieved %s after %d days)\n", goal.Description, goal.Completed, goal.Days)
+		}
+	}
+
+	fmt.Println(theme.Title("\nProjects sent:"))
+	fmt.Printf("  %s\n", strings.Join(gate.Projects(env.UserProjects), ", "))
+
+	var tags []string
+	for tag := range env.Config.Tags {
+		if gate.AllowTag(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	fmt.Println(theme.Title("\nTags sent:"))
+	fmt.Printf("  %s\n", strings.Join(tags, ", "))
+}
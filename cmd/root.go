@@ -26,10 +26,14 @@ AI-powered guidance, motivation, and task categorization using LLM APIs.
 • spot     - Picks one high impact, high urgency task to do right now
 • guide    - Asks a series of questions -> generates roadmap to achieve goal
 • goals    - Manage strategic goals and link tasks to them
+• privacy  - Audit which tasks and fields are sent to the LLM
+• config   - Show, change and validate the configuration
+• templates - Customize the prompt templates sent to the LLM
 
 🔧 CONFIGURATION:
-Config stored at: ~/.config/taskvanguard/vanguardrc.yaml
-Supports OpenAI and DeepSeek LLM providers
+Config stored at: ~/.config/taskvanguard/vanguardrc.yaml, merged over
+/etc/taskvanguard/vanguardrc.yaml and under the nearest .vanguardrc.yaml
+Supports OpenAI, DeepSeek and Ollama LLM providers, configurable per command
 
 ⚔️ QUICK START:
 1. Run 'taskvanguard init' to set up configuration
@@ -48,6 +52,19 @@ func init() {
 	rootCmd.AddCommand(spotCmd)
 	rootCmd.AddCommand(goalsCmd)
 	rootCmd.AddCommand(guideCmd)
+	rootCmd.AddCommand(privacyCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(templatesCmd)
+
+	for _, cmd := range []*cobra.Command{addCmd, analyzeCmd, spotCmd, guideCmd} {
+		addLLMFlags(cmd)
+	}
+}
+
+// addLLMFlags adds the flags overriding the configured LLM to a command that talks to it
+func addLLMFlags(cmd *cobra.Command) {
+	cmd.Flags().String("model", "", "Use this LLM model for this command")
+	cmd.Flags().String("provider", "", "Use this LLM provider for this command (openai, deepseek, ollama)")
 }
 
 func Execute() error {
@@ -14,6 +14,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/taskvanguard/taskvanguard/internal/goals"
 	"github.com/taskvanguard/taskvanguard/internal/llm"
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
+	"github.com/taskvanguard/taskvanguard/internal/prompts"
 	"github.com/taskvanguard/taskvanguard/internal/state"
 	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
 	"github.com/taskvanguard/taskvanguard/pkg/theme"
@@ -33,11 +35,12 @@ type SpotlightResult struct {
 
 
 var spotCmd = &cobra.Command{
-	Use:   "spot",
+	Use:   "spot [filter | @preset...]",
 	Short: "Analyze urgent tasks and suggests the best one",
 	Long: `Analyzes the tasklist, presents a single, high impact task and helps with tackling it. 
 	Reframes it, breaks it down into Microtasks and gives some beneficial infos`,
 	Run: runSpot,
+	ValidArgsFunction: completeFilterPresets,
 }
 
 func init() {
@@ -78,7 +81,7 @@ func runPassiveSpotlight(client *taskwarrior.Client, cfg *types.Config, moodFlag
 	s.Prefix = "Working... "
 	s.Start()
 
-	task, err := pickSpotlightTask(client, cfg, taskContext, filterArgs)
+	task, _, err := pickSpotlightTask(client, cfg, taskContext, filterArgs)
 	if err != nil {
     	fmt.Println("❌", theme.Error(err.Error()))
     	return
@@ -97,86 +100,146 @@ func runInteractiveSpotlight(client *taskwarrior.Client, cfg *types.Config, mood
 	}
 	taskContext := askOrLoadContextFromState(stateManager, moodFlag, contextFlag, refresh)
 
-	s := spinner.New(spinner.CharSets[40], 100*time.Millisecond) 
-	s.Prefix = "Working... "
-	s.Start()
+	for {
+		s := spinner.New(spinner.CharSets[40], 100*time.Millisecond) 
+		s.Prefix = "Working... "
+		s.Start()
 
-	task, err := pickSpotlightTask(client, cfg, taskContext, filterArgs)
-	if err != nil {
-    	fmt.Println("❌", theme.Error(err.Error()))
-    	return
-	}
+		task, fetched, err := pickSpotlightTask(client, cfg, taskContext, filterArgs)
+		if err != nil {
+			s.Stop()
+			fmt.Println("❌", theme.Error(err.Error()))
+			return
+		}
 
-	s.Stop()
+		s.Stop()
 
-	displaySpotlight(task, false)
-	promptUserAction(client, task)
+		displaySpotlight(task, false)
+		if reanalyze, _ := promptUserAction(client, task, fetched); !reanalyze {
+			return
+		}
+	}
 }
 
-func pickSpotlightTask(client *taskwarrior.Client, cfg *types.Config, taskContext state.TaskContext, filterArgs []string) (SpotlightResult, error) {
+// spotlightCandidates returns the pending tasks the LLM may choose from, most urgent first,
+// together with the tasks blocked by the privacy filters
+func spotlightCandidates(client *taskwarrior.Client, cfg *types.Config, gate *privacy.Gate, args []string) ([]types.Task, []privacy.Blocked, error) {
+	filterArgs, err := client.ResolveFilter(cfg, args)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	var tasks []types.Task
-	var err error
+	tasks, err := client.GetPendingTasksWithArgs(filterArgs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch tasks: %w", err)
+	}
 
-	if len(filterArgs) > 0 {
-		tasks, err = client.GetPendingTasksWithArgsFiltered(cfg, filterArgs)
-	} else {
-		tasks, err = client.GetPendingTasksFiltered(cfg)
+	allowed, blocked := gate.Partition(tasks)
+
+	sort.Slice(allowed, func(i, j int) bool {
+		return allowed[i].Urgency > allowed[j].Urgency
+	})
+
+	if len(allowed) > 20 {
+		allowed = allowed[:20]
 	}
 
-	if err != nil {
-		return SpotlightResult{}, fmt.Errorf("fetch tasks: %w", err)
+	return allowed, blocked, nil
+}
+
+// spotlightViews reduces the candidates to what is sent to the LLM, including the descriptions of linked goals
+func spotlightViews(cfg *types.Config, gate *privacy.Gate, tasks []types.Task) []privacy.TaskView {
+	byUUID := make(map[string]types.Task)
+	if userGoals, err := goals.NewManager(cfg).ListGoals(); err == nil {
+		for _, goal := range userGoals {
+			byUUID[goal.UUID] = goal
+		}
 	}
 
-	if len(tasks) == 0 {
-		return SpotlightResult{}, fmt.Errorf("no pending tasks: %w", err)
+	views := make([]privacy.TaskView, 0, len(tasks))
+	for _, task := range tasks {
+		var goalDescriptions []string
+		for _, uuid := range task.GoalUUIDs() {
+			if linkedGoal, ok := byUUID[uuid]; ok && gate.Check(linkedGoal.Project, linkedGoal.Tags) == nil {
+				goalDescriptions = append(goalDescriptions, linkedGoal.Description)
+			}
+		}
+		views = append(views, gate.View(task, strings.Join(goalDescriptions, "; ")))
 	}
+	return views
+}
 
-	sort.Slice(tasks, func(i, j int) bool {
-		return tasks[i].Urgency > tasks[j].Urgency
-	})
+// recentCompletions returns the descriptions of the tasks completed in the last two days the gate lets through
+func recentCompletions(client *taskwarrior.Client, gate *privacy.Gate) []string {
+	completed, err := client.GetTasksWithFilter([]string{"status:completed", "end.after:now-2d"})
+	if err != nil {
+		return nil
+	}
+	allowed, _ := gate.Partition(completed)
 
-	if len(tasks) > 20 {
-		tasks = tasks[:20]
+	var descriptions []string
+	for _, task := range allowed {
+		descriptions = append(descriptions, gate.Text(task.Description))
 	}
+	return descriptions
+}
 
-	llmClient, err := llm.NewClient(&cfg.LLM)
+// pickSpotlightTask lets the LLM choose a task and returns it together with the task as it was fetched (nil if unknown)
+func pickSpotlightTask(client *taskwarrior.Client, cfg *types.Config, taskContext state.TaskContext, filterArgs []string) (SpotlightResult, *types.Task, error) {
+	gate, err := privacy.NewGate(cfg)
 	if err != nil {
-		return SpotlightResult{}, fmt.Errorf("init llm client: %w", err)
+		return SpotlightResult{}, nil, err
 	}
 
-	prompt := createSpotlightPrompt(taskContext, tasks, cfg)
-	messages := []llm.Message{
-		{Role: "user", Content: prompt},
+	tasks, _, err := spotlightCandidates(client, cfg, gate, filterArgs)
+	if err != nil {
+		return SpotlightResult{}, nil, err
 	}
 
-	if cfg.Settings.Debug {
-		fmt.Println(theme.Title("LLM Request"))
-		fmt.Println(theme.Info(messages))
+	if len(tasks) == 0 {
+		return SpotlightResult{}, nil, fmt.Errorf("no pending tasks")
 	}
 
-	if !cfg.Settings.EnableLLM {
-		return SpotlightResult{}, fmt.Errorf("sending API Request to LLM is disabled via config")
+	views := spotlightViews(cfg, gate, tasks)
+	var deferred []string
+	for _, view := range views {
+		if view.Skipped > 0 {
+			deferred = append(deferred, view.Description)
+		}
 	}
 
-	response, err := llmClient.Chat(messages)
-	
-	if cfg.Settings.Debug {
-		fmt.Println(theme.Title("LLM Response"))
-		fmt.Println(theme.Info(response))
+	prompt, err := prompts.RenderTemplate("spotlight.md", prompts.SpotlightData{
+		Mood:          taskContext.Mood,
+		Location:      taskContext.Location,
+		Time:          time.Now().Format("3:04 PM"),
+		RecentTasks:   recentCompletions(client, gate),
+		DeferredTasks: deferred,
+		Tasks:         views,
+		AchievedGoals: achievedGoals(cfg, gate),
+	})
+	if err != nil {
+		return SpotlightResult{}, nil, err
 	}
+
+	response, err := gate.Send("spotlight.md", prompt)
 	if err != nil {
-		return SpotlightResult{}, fmt.Errorf("llm chat error: %w", err)
+		return SpotlightResult{}, nil, fmt.Errorf("llm chat error: %w", err)
 	}
 
 	cleanResponse := llm.CleanResponse(response)
 
 	var result SpotlightResult
 	if err := json.Unmarshal([]byte(cleanResponse), &result); err != nil {
-		return SpotlightResult{}, fmt.Errorf("unmarshall llm response: %w", err)
+		return SpotlightResult{}, nil, fmt.Errorf("unmarshall llm response: %w", err)
 	}
 
-	return result, nil
+	for i := range tasks {
+		if tasks[i].ID == result.TaskID {
+			return result, &tasks[i], nil
+		}
+	}
+
+	return result, nil, nil
 }
 
 func displaySpotlight(t SpotlightResult, silent bool) {
@@ -286,72 +349,9 @@ func loadContextFromState(stateManager *state.StateManager, moodFlag, contextFla
 	return context
 }
 
-func createSpotlightPrompt(taskContext state.TaskContext, tasks []types.Task, cfg *types.Config) string {
-	// Create enhanced tasks with goal descriptions for LLM
-	type TaskForLLM struct {
-		types.Task
-		GoalDescription string `json:"goal_description,omitempty"`
-	}
-
-	var enhancedTasks []TaskForLLM
-	goalsManager := goals.NewManager(cfg)
-
-	for _, task := range tasks {
-		enhanced := TaskForLLM{Task: task}
-		
-		// If task has a goal, get its description
-		if task.Goal != "" {
-			linkedGoal, err := goalsManager.GetLinkedGoal(strconv.Itoa(task.ID))
-			if err == nil && linkedGoal != nil {
-				enhanced.GoalDescription = linkedGoal.Description
-			}
-		}
-		
-		enhancedTasks = append(enhancedTasks, enhanced)
-	}
-
-	tasksJSON, err := json.MarshalIndent(enhancedTasks, "  ", "  ")
-	if err != nil {
-		tasksJSON = []byte("[]")
-	}
-
-	prompt := fmt.Sprintf(`You are a productivity expert helping me to choose the best task to work on right now.
-
-Context:
-- Current mood: %s
-- Current location: %s  
-- Time: %s
-
-Here are the top pending tasks sorted by urgency (JSON format):
-
-%s`, taskContext.Mood, taskContext.Location, time.Now().Format("3:04 PM"), string(tasksJSON))
-
-	prompt += `
-Analyze the provided tasks and select the ONE best task for right now based on current context.
-
-Rules:
-- Address the user as "you"
-- Return only valid JSON
-- Use empty strings ("") for optional fields when no data exists
-- For "history" field: if task was previously skipped, explain why; otherwise use empty string
-- Be direct and concise
-
-Required JSON format:
-{
-  "task_id": number,
-  "title": "string - simplified task title",
-  "reason": "string - why this task fits now",
-  "estimated": "string - time estimate format: 'X min' or 'X hour'",
-  "history": "string - reasons for skipping if any, otherwise empty",
-  "goal": "string - associated goal if relevant, otherwise empty", 
-  "context_tag": "string - single word context descriptor",
-  "next": "string - immediate first action to take"
-}`
-
-	return prompt
-}
-
-func promptUserAction(client *taskwarrior.Client, spotlightTask SpotlightResult) error {
+// promptUserAction applies the chosen action to the spotlight task. It returns true if the user wants a new spotlight
+// because the task was modified in the meantime.
+func promptUserAction(client *taskwarrior.Client, spotlightTask SpotlightResult, fetched *types.Task) (bool, error) {
 
 	fmt.Printf("%s %s %s: ", theme.Title("→"), theme.Info("Do this task now?"), "[Y]es/[s]kip/[n]ext (tag +next)")
 
@@ -359,17 +359,37 @@ func promptUserAction(client *taskwarrior.Client, spotlightTask SpotlightResult)
 	fmt.Scanln(&response)
 	response = strings.TrimSpace(strings.ToLower(response))
 
+	switch response {
+	case "y", "yes", "", "n", "next", "s", "skip":
+		// The task may have been edited elsewhere while the LLM was picking it
+		if fetched != nil {
+			stale, err := client.FindStale([]types.Task{*fetched})
+			if err != nil {
+				return false, err
+			}
+			if len(stale) > 0 {
+				switch askConflictAction(bufio.NewReader(os.Stdin), stale[0].Fetched, stale[0].Current) {
+				case conflictReanalyze:
+					return true, nil
+				case conflictSkip:
+					fmt.Println(theme.Warn("Task left as it is."))
+					return false, nil
+				}
+			}
+		}
+	}
+
 	var isTaskSkipped bool
 
-	switch strings.ToLower(response) {
+	switch response {
 	case "y", "yes", "":
 		client.StartTask(strconv.Itoa(spotlightTask.TaskID))
 		fmt.Println(theme.Success("Momentum: Task started!"))
-		return nil
+		return false, nil
 	case "n", "next":
 		client.ModifyTaskInTaskWarrior(spotlightTask.TaskID, []string{"+next"})
 		fmt.Println(theme.Warn("Task marked with +next."))
-		return nil
+		return false, nil
 	case "s", "skip":
 		isTaskSkipped = true
 		fmt.Printf("%s %s %s", theme.Error("Blocked."), theme.Info("What's stopping you?"), "[quick note]: ")
@@ -389,9 +409,9 @@ func promptUserAction(client *taskwarrior.Client, spotlightTask SpotlightResult)
 			arg := "skipped:" + strconv.Itoa(int(task.Skipped))
 			client.ModifyTaskInTaskWarrior(spotlightTask.TaskID, []string{arg})
 		} else {
-			return err
+			return false, err
 		}
 	}
 	
-	return nil
+	return false, nil
 }
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taskvanguard/taskvanguard/internal/config"
+	"github.com/taskvanguard/taskvanguard/internal/prompts"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+	"github.com/taskvanguard/taskvanguard/pkg/utils"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Customize the prompt templates sent to the LLM",
+	Long: `Prompt templates are looked up in the templates directory next to the config file
+(~/.config/taskvanguard/templates, or TASKVANGUARD_TEMPLATES) first and fall back to the built-in ones.
+Export a template, edit it there and it is used from the next run on. Overrides are parsed when loaded,
+a broken override fails the command instead of sending a garbled prompt.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the templates and whether they are overridden",
+	Args:  cobra.NoArgs,
+	Run:   runTemplatesList,
+}
+
+var templatesExportCmd = &cobra.Command{
+	Use:               "export [name...]",
+	Short:             "Copy built-in templates to the templates directory to customize them",
+	ValidArgsFunction: completeTemplateNames,
+	Run:               runTemplatesExport,
+}
+
+var templatesDiffCmd = &cobra.Command{
+	Use:               "diff <name>",
+	Short:             "Show how an override differs from the built-in template",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplateNames,
+	Run:               runTemplatesDiff,
+}
+
+var templatesResetCmd = &cobra.Command{
+	Use:               "reset [name...]",
+	Short:             "Remove overrides so the built-in templates apply again",
+	ValidArgsFunction: completeTemplateNames,
+	Run:               runTemplatesReset,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesExportCmd)
+	templatesCmd.AddCommand(templatesDiffCmd)
+	templatesCmd.AddCommand(templatesResetCmd)
+
+	templatesExportCmd.Flags().Bool("all", false, "Export every template")
+	templatesExportCmd.Flags().Bool("force", false, "Replace existing overrides")
+	templatesResetCmd.Flags().Bool("all", false, "Remove every override")
+	templatesResetCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+}
+
+// completeTemplateNames offers the built-in template names
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := config.DefaultTemplateNames()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// templateNamesOrExit returns the templates named in args, or all of names with --all.
+// unknown is the message format for a name not in names.
+func templateNamesOrExit(cmd *cobra.Command, args []string, names []string, unknown string) []string {
+	all, _ := cmd.Flags().GetBool("all")
+	if all {
+		return names
+	}
+	if len(args) == 0 {
+		fmt.Println(theme.Error("Name at least one template or use --all, see 'vanguard templates list'"))
+		os.Exit(1)
+	}
+	for _, name := range args {
+		if !slices.Contains(names, name) {
+			fmt.Println(theme.Error(fmt.Sprintf(unknown, name)))
+			os.Exit(1)
+		}
+	}
+	return args
+}
+
+func runTemplatesList(cmd *cobra.Command, args []string) {
+	defaults, err := config.DefaultTemplateNames()
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	overrides, err := config.UserTemplateNames()
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	dir, _ := config.TemplatesDir()
+
+	width := 0
+	for _, name := range append(defaults, overrides...) {
+		width = max(width, len(name))
+	}
+
+	fmt.Println(theme.Title("Templates") + theme.Unimportant(" (overrides in "+dir+")"))
+	for _, name := range defaults {
+		content, _, ok, err := config.ReadUserTemplate(name)
+		if err == nil && ok {
+			if err = prompts.ValidateTemplate(name, content); err != nil {
+				err = fmt.Errorf("overridden, invalid: %v", err)
+			}
+		}
+		switch {
+		case err != nil:
+			fmt.Printf("  %-*s  %s\n", width, name, theme.Error(err.Error()))
+		case !ok:
+			fmt.Printf("  %-*s  %s\n", width, name, theme.Unimportant("built-in"))
+		default:
+			fmt.Printf("  %-*s  %s\n", width, name, theme.Success("overridden"))
+		}
+	}
+	for _, name := range overrides {
+		if replacement, ok := config.RetiredTemplates[name]; ok {
+			fmt.Printf("  %-*s  %s\n", width, name, theme.Warn("not used, merged into "+replacement+", move your changes there"))
+		} else if !slices.Contains(defaults, name) {
+			fmt.Printf("  %-*s  %s\n", width, name, theme.Warn("not used, there is no built-in template of this name"))
+		}
+	}
+}
+
+func runTemplatesExport(cmd *cobra.Command, args []string) {
+	defaults, err := config.DefaultTemplateNames()
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	force, _ := cmd.Flags().GetBool("force")
+
+	failed := false
+	for _, name := range templateNamesOrExit(cmd, args, defaults, "Unknown template %q, see 'vanguard templates list'") {
+		path, err := config.ExportTemplate(name, force)
+		switch {
+		case errors.Is(err, fs.ErrExist):
+			fmt.Println(theme.Warn(fmt.Sprintf("%s is already overridden in %s, use --force to replace it", name, path)))
+		case err != nil:
+			fmt.Println(theme.Error(fmt.Sprintf("Failed to export %s: %v", name, err)))
+			failed = true
+		default:
+			fmt.Println(theme.Success("✓ Exported " + path))
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func runTemplatesDiff(cmd *cobra.Command, args []string) {
+	name := args[0]
+	builtin, err := config.ReadDefaultTemplate(name)
+	if err != nil {
+		fmt.Println(theme.Error(fmt.Sprintf("Unknown template %q, see 'vanguard templates list'", name)))
+		os.Exit(1)
+	}
+	override, path, ok, err := config.ReadUserTemplate(name)
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	if !ok {
+		fmt.Println(theme.Info(name + " is not overridden"))
+		return
+	}
+
+	diff := utils.UnifiedDiff("built-in/"+name, path, builtin, override, 3)
+	if diff == "" {
+		fmt.Println(theme.Info(path + " is the same as the built-in template"))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			fmt.Println(theme.Info(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Println(theme.Error(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Println(theme.Success(line))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+func runTemplatesReset(cmd *cobra.Command, args []string) {
+	overrides, err := config.UserTemplateNames()
+	if err != nil {
+		fmt.Println(theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	names := templateNamesOrExit(cmd, args, overrides, "%s is not overridden")
+	if len(names) == 0 {
+		fmt.Println(theme.Info("No templates are overridden"))
+		return
+	}
+
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		fmt.Printf("%s Remove the overrides of %s? [y]es/[N]o: ", theme.Title("→"), strings.Join(names, ", "))
+		input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(input)); answer != "y" && answer != "yes" {
+			return
+		}
+	}
+
+	for _, name := range names {
+		path, err := config.ResetTemplate(name)
+		if err != nil {
+			fmt.Println(theme.Error(fmt.Sprintf("Failed to reset %s: %v", name, err)))
+			os.Exit(1)
+		}
+		fmt.Println(theme.Success("✓ Removed " + path))
+	}
+}
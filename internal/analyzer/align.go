@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
+	"github.com/taskvanguard/taskvanguard/internal/prompts"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// GoalLink is a proposed link from a task to a goal
+type GoalLink struct {
+	TaskID     int     `json:"task_id"`
+	GoalID     int     `json:"goal_id"`
+	Confidence float64 `json:"confidence"` // 0 to 1
+	Rationale  string  `json:"rationale"`
+
+	Task types.Task `json:"-"`
+	Goal types.Task `json:"-"`
+}
+
+// UnalignedTask is a task the LLM found no goal for
+type UnalignedTask struct {
+	TaskID int    `json:"task_id"`
+	Note   string `json:"note"`
+
+	Task types.Task `json:"-"`
+}
+
+// GoalAlignment is the LLM's proposal for how tasks support goals
+type GoalAlignment struct {
+	Links     []GoalLink      `json:"links"`
+	Unaligned []UnalignedTask `json:"unaligned_tasks"`
+}
+
+// AlignmentPayload returns the goals and tasks AlignTasksWithGoals sends to the LLM
+func AlignmentPayload(gate *privacy.Gate, tasks []types.Task, goals []types.Task) prompts.AlignmentData {
+	data := prompts.AlignmentData{
+		Goals: prompts.ToPromptGoals(gate.Goals(goals)),
+		Tasks: make([]privacy.TaskView, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		data.Tasks = append(data.Tasks, gate.View(task, ""))
+	}
+	return data
+}
+
+// AlignTasksWithGoals asks the LLM which goal each task supports. Tasks are sent in batches of
+// batchSize together with all goals the privacy filters allow. Proposals referring to unknown IDs are dropped.
+func AlignTasksWithGoals(gate *privacy.Gate, tasks []types.Task, goals []types.Task, batchSize int) (*GoalAlignment, error) {
+	goalsByID := make(map[int]types.Task, len(goals))
+	for _, goal := range goals {
+		goalsByID[goal.ID] = goal
+	}
+	if len(gate.Goals(goals)) == 0 {
+		return nil, fmt.Errorf("no goals may be sent to the LLM")
+	}
+
+	if batchSize <= 0 {
+		batchSize = len(tasks)
+	}
+
+	alignment := &GoalAlignment{}
+	for i := 0; i < len(tasks); i += batchSize {
+		end := min(i+batchSize, len(tasks))
+
+		tasksByID := make(map[int]types.Task, end-i)
+		for _, task := range tasks[i:end] {
+			tasksByID[task.ID] = task
+		}
+
+		prompt, err := prompts.RenderTemplate("goal_alignment.md", AlignmentPayload(gate, tasks[i:end], goals))
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := gate.Send("goal_alignment.md", prompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process batch %d-%d: %v", i+1, end, err)
+		}
+
+		var batch GoalAlignment
+		if err := json.Unmarshal([]byte(cleanMarkdownCodeFences(response)), &batch); err != nil {
+			return nil, fmt.Errorf("failed to parse LLM response for batch %d-%d: %v", i+1, end, err)
+		}
+
+		for _, link := range batch.Links {
+			task, taskOK := tasksByID[link.TaskID]
+			goal, goalOK := goalsByID[link.GoalID]
+			if !taskOK || !goalOK {
+				continue
+			}
+			// Some models answer in percent
+			if link.Confidence > 1 {
+				link.Confidence /= 100
+			}
+			link.Confidence = max(0, min(1, link.Confidence))
+			link.Task, link.Goal = task, goal
+			alignment.Links = append(alignment.Links, link)
+		}
+		for _, unaligned := range batch.Unaligned {
+			if task, ok := tasksByID[unaligned.TaskID]; ok {
+				unaligned.Task = task
+				alignment.Unaligned = append(alignment.Unaligned, unaligned)
+			}
+		}
+	}
+
+	return alignment, nil
+}
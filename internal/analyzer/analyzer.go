@@ -6,10 +6,8 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/taskvanguard/taskvanguard/internal/llm"
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
 	"github.com/taskvanguard/taskvanguard/internal/prompts"
-	"github.com/taskvanguard/taskvanguard/pkg/filter"
-	"github.com/taskvanguard/taskvanguard/pkg/theme"
 	"github.com/taskvanguard/taskvanguard/pkg/types"
 	"github.com/taskvanguard/taskvanguard/pkg/utils"
 )
@@ -34,28 +32,25 @@ func cleanMarkdownCodeFences(response string) string {
 }
 
 func AnalyzeSingleTaskWithLLM(cfg *types.Config, taskArgs string, userGoals []types.Task, projects []string) (*types.TaskSuggestion, error) {
-	args := utils.ParseTaskArgs(taskArgs)
-
-	if !filter.ShouldIncludeByTags(args.Tags, cfg.Filters) {
-		return nil, fmt.Errorf("at least one of the tags is blacklisted for LLM processing: tags=%v", args.Tags)
-	}
-
-	if !filter.ShouldIncludeByProject(args.Project, cfg.Filters) {
-		return nil, fmt.Errorf("the project this task is assigned to is blacklisted for LLM processing: project=%q", args.Project)
+	gate, err := privacy.NewGate(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	task := prompts.Task{
-		Description: args.Title,
-		Tags:        args.Tags,
-		Project:     args.Project,
-		Priority:    args.Priority,
-		// DueDate:     "2025-06-15",
+	task, err := PromptTask(gate, taskArgs)
+	if err != nil {
+		return nil, err
 	}
 
-	data := buildTemplateData(cfg, []prompts.Task{task}, userGoals, projects)
+	data := buildTemplateData(cfg, gate, []prompts.Task{task}, userGoals, projects)
 	data.Task = task
 
-	response, err := sendLLMRequest(cfg, "task_analysis_single.md", data)
+	templateName := "task_analysis_single.md"
+	if cfg.Settings.ExtendedAnalysis {
+		templateName = "task_analysis_extended.md"
+	}
+
+	response, err := sendLLMRequest(gate, templateName, data)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +70,11 @@ func AnalyzeBatchTasksWithLLM(cfg *types.Config, taskArgsList []string, userGoal
 	const batchSize = 20
 	var allAnalyses []types.TaskAnalysisResult
 
+	gate, err := privacy.NewGate(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Process tasks in chunks of 15
 	for i := 0; i < len(taskArgsList); i += batchSize {
 		end := i + batchSize
@@ -87,29 +87,17 @@ func AnalyzeBatchTasksWithLLM(cfg *types.Config, taskArgsList []string, userGoal
 		taskIndexOffset := i
 
 		for _, taskArgs := range batchTaskArgs {
-			args := utils.ParseTaskArgs(taskArgs)
-
-			if !filter.ShouldIncludeByTags(args.Tags, cfg.Filters) {
-				return nil, fmt.Errorf("at least one of the tags is blacklisted for LLM processing: tags=%v", args.Tags)
+			task, err := PromptTask(gate, taskArgs)
+			if err != nil {
+				return nil, err
 			}
-
-			if !filter.ShouldIncludeByProject(args.Project, cfg.Filters) {
-				return nil, fmt.Errorf("the project this task is assigned to is blacklisted for LLM processing: project=%q", args.Project)
-			}
-
-			tasks = append(tasks, prompts.Task{
-				Description: args.Title,
-				Tags:        args.Tags,
-				Project:     args.Project,
-				Priority:    args.Priority,
-				// DueDate:     "2025-06-15",
-			})
+			tasks = append(tasks, task)
 		}
 
-		data := buildTemplateData(cfg, tasks, userGoals, projects)
+		data := buildTemplateData(cfg, gate, tasks, userGoals, projects)
 		data.Tasks = tasks
 
-		response, err := sendLLMRequest(cfg, "task_analysis_batch.md", data)
+		response, err := sendLLMRequest(gate, "task_analysis_batch.md", data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to process batch %d-%d: %v", i+1, end, err)
 		}
@@ -135,6 +123,23 @@ func AnalyzeBatchTasksWithLLM(cfg *types.Config, taskArgsList []string, userGoal
 	}, nil
 }
 
+// PromptTask turns task arguments into the task sent to the LLM, or returns an error if the gate blocks it
+func PromptTask(gate *privacy.Gate, taskArgs string) (prompts.Task, error) {
+	args := utils.ParseTaskArgs(taskArgs)
+
+	if err := gate.Check(args.Project, args.Tags); err != nil {
+		return prompts.Task{}, err
+	}
+
+	return prompts.Task{
+		Description: gate.Text(args.Title),
+		Tags:        args.Tags,
+		Project:     args.Project,
+		Priority:    args.Priority,
+		// DueDate:     "2025-06-15",
+	}, nil
+}
+
 func BuildExampleJSON(userAnnotations []prompts.Annotation) string {
 	buf := &bytes.Buffer{}
 	buf.WriteString("{\n")
@@ -151,19 +156,30 @@ func BuildExampleJSON(userAnnotations []prompts.Annotation) string {
 	return buf.String()
 }
 
-func buildTemplateData(cfg *types.Config, tasks []prompts.Task, userGoals []types.Task, projects []string) prompts.TemplateData {
+func buildTemplateData(cfg *types.Config, gate *privacy.Gate, tasks []prompts.Task, userGoals []types.Task, projects []string) prompts.TemplateData {
+	// Completed goals are no longer worked on, they only show up as history if enabled
+	var openGoals []types.Task
+	for _, goal := range userGoals {
+		if goal.Status != "completed" {
+			openGoals = append(openGoals, goal)
+		}
+	}
+
 	data := prompts.TemplateData{
 		UserContext: prompts.UserContext{
 			UserTags:        []prompts.Tag{},
 			UserAnnotations: []prompts.Annotation{},
-			UserProjects:    projects,
-			UserGoals:       prompts.ToPromptGoals(userGoals),
+			UserProjects:    gate.Projects(projects),
+			UserGoals:       prompts.ToPromptGoals(gate.Goals(openGoals)),
 		},
 	}
+	if cfg.Settings.GoalHistoryInPrompts {
+		data.UserContext.AchievedGoals = prompts.ToAchievedGoals(gate.Goals(userGoals), cfg.Settings.GoalHistoryLimit)
+	}
 
 	// Add Tags and Annotations from config
 	for name, meta := range cfg.Tags {
-		if !filterTags(name, cfg.Filters.TagFilterMode, cfg.Filters.TagFilterTags) {
+		if !gate.AllowTag(name) {
 			continue
 		}
 
@@ -185,60 +201,11 @@ func buildTemplateData(cfg *types.Config, tasks []prompts.Task, userGoals []type
 	return data
 }
 
-func sendLLMRequest(cfg *types.Config, templateName string, data prompts.TemplateData) (string, error) {
-	llmClient, err := llm.NewClient(&cfg.LLM)
-	if err != nil {
-		return "", err
-	}
-
+func sendLLMRequest(gate *privacy.Gate, templateName string, data prompts.TemplateData) (string, error) {
 	rendered, err := prompts.RenderTemplate(templateName, data)
 	if err != nil {
 		return "", err
 	}
 
-	messages := []llm.Message{
-		{Role: "user", Content: rendered},
-	}
-
-	if cfg.Settings.Debug {
-		fmt.Println(theme.Title("LLM Request"))
-		fmt.Println(theme.Info(messages))
-	}
-
-	if !cfg.Settings.EnableLLM {
-		return "", fmt.Errorf("sending API Request to LLM is disabled via config")
-	}
-
-	response, err := llmClient.Chat(messages)
-	if cfg.Settings.Debug {
-		fmt.Println(theme.Title("LLM Response"))
-		fmt.Println(theme.Info(response))
-	}
-	if err != nil {
-		return "", err
-	}
-
-	return response, nil
+	return gate.Send(templateName, rendered)
 }
-
-func filterTags(tagName string, mode string, list []string) bool {
-	switch mode {
-	case "blacklist":
-		for _, v := range list {
-			if tagName == v {
-				return false // skip if blacklisted
-			}
-		}
-		return true // include if not blacklisted
-	case "whitelist":
-		for _, v := range list {
-			if tagName == v {
-				return true // include if whitelisted
-			}
-		}
-		return false // skip if not whitelisted
-	default:
-		return true // include everything if mode is unknown
-	}
-}
-
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -8,44 +9,149 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// Path returns the location of the config file, TASKVANGUARD_CONFIG takes precedence
+func Path() (string, error) {
+	if envPath := os.Getenv("TASKVANGUARD_CONFIG"); envPath != "" {
+		return envPath, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "taskvanguard", "vanguardrc.yaml"), nil
+}
+
+// Load merges the config layers and TASKVANGUARD_* variables over the defaults and validates the result.
+// A default user config is created on first use.
 func Load() (*types.Config, error) {
-	var configPath string
+	configPath, err := Path()
+	if err != nil {
+		return nil, err
+	}
 	
-	if envPath := os.Getenv("TASKVANGUARD_CONFIG"); envPath != "" {
-		configPath = envPath
-	} else {
-		configDir, err := os.UserConfigDir()
-		if err != nil {
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if _, err := CreateDefaultConfig(configPath); err != nil {
 			return nil, err
 		}
-		configPath = filepath.Join(configDir, "taskvanguard", "vanguardrc.yaml")
 	}
-	
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return CreateDefaultConfig(configPath)
+
+	loaded, err := LoadLayers()
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := os.ReadFile(configPath)
+	if err := Validate(loaded.Config); err != nil {
+		return nil, fmt.Errorf("%s: %w", loaded.Describe(), err)
+	}
+
+	return loaded.Config, nil
+}
+
+// LoadFile reads a single config file, migrates it to CurrentVersion if needed and merges it over the defaults.
+// The result is not validated.
+func LoadFile(configPath string) (*types.Config, error) {
+	raw, err := readLayer(Layer{Name: "user", Path: configPath})
 	if err != nil {
 		return nil, err
 	}
 
-	var config types.Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	config := Defaults()
+	if err := mergeLayer(config, raw, make(map[string]bool)); err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+	return config, nil
+}
+
+// entryMaps are maps of named entries. The first layer setting one replaces the default entries,
+// so removing a default tag from the config removes it for good. Later layers add or replace single entries.
+var entryMaps = map[string]struct {
+	reset  func(*types.Config)
+	remove func(*types.Config, string)
+}{
+	"tags":        {func(c *types.Config) { c.Tags = nil }, func(c *types.Config, name string) { delete(c.Tags, name) }},
+	"annotations": {func(c *types.Config) { c.Annotations = nil }, func(c *types.Config, name string) { delete(c.Annotations, name) }},
+	"presets":     {func(c *types.Config) { c.Presets = nil }, func(c *types.Config, name string) { delete(c.Presets, name) }},
+}
+
+// mergeLayer decodes a raw config layer over cfg. Keys missing in raw keep the value from cfg,
+// null entries of entryMaps remove the entry. replaced tracks the entryMaps already set by earlier layers.
+func mergeLayer(cfg *types.Config, raw map[string]interface{}, replaced map[string]bool) error {
+	for key, entries := range entryMaps {
+		section, ok := raw[key]
+		if !ok {
+			continue
+		}
+		if !replaced[key] {
+			entries.reset(cfg)
+			replaced[key] = true
+		}
+		if named, ok := section.(map[string]interface{}); ok {
+			for name, entry := range named {
+				if entry == nil {
+					entries.remove(cfg, name)
+					delete(named, name)
+				}
+			}
+		}
+	}
+
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, cfg)
+}
+
+func readRaw(configPath string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
 		return nil, err
 	}
 
-	return &config, nil
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+	return raw, nil
 }
 
-func CreateDefaultConfig(configPath string) (*types.Config, error) {
-	config := &types.Config{
+// writeMigrated keeps a copy of the original file and writes the migrated config in its place
+func writeMigrated(configPath string, raw map[string]interface{}) (string, error) {
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		return "", err
+	}
+
+	backupPath := configPath + ".bak"
+	if err := os.WriteFile(backupPath, original, 0600); err != nil {
+		return "", err
+	}
+
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return "", err
+	}
+	return backupPath, os.WriteFile(configPath, data, 0600)
+}
+
+// Defaults returns the built-in configuration that loaded config files are merged over
+func Defaults() *types.Config {
+	return &types.Config{
+		ConfigVersion: CurrentVersion,
 		Settings: types.Settings{
 			Debug: false,
 			SplitTasks: true,
 			EnableLLM: true,
 			EnableGoals: true,
 			GoalProjectName: "goals",
+			GoalMode: types.GoalModeProject,
+			GoalTag: "goal",
+			GoalUDA: "type",
+			GoalEstimateUDA: "estimate",
+			GoalVelocityWeeks: 4,
+			GoalReviewDays: 30,
+			GoalStaleDays: 14,
+			GoalHistoryLimit: 5,
 			TaskImportLimit: 500,
 			TaskProcessingBatchSize: 15,
 			GuidingQuestionAmount: 6,
@@ -54,8 +160,7 @@ func CreateDefaultConfig(configPath string) (*types.Config, error) {
 		LLM: types.LLMConfig{
 			Provider: "openai",
 			Model:    "gpt-4.1-mini",
-			BaseURL:  "https://openrouter.ai/api/v1",
-			APIKey:   "<api key>",
+			APIKey:   "",
 		},
 		Filters: types.FiltersConfig{
 			TagFilterMode: "blacklist",
@@ -63,6 +168,10 @@ func CreateDefaultConfig(configPath string) (*types.Config, error) {
 			ProjectFilterMode: "blacklist",
 			ProjectFilterProjects: []string{"pers.secret", "work.secret"},
 		},
+		Redaction: types.RedactionConfig{
+			Enabled: true,
+			Detectors: []string{"email", "url", "phone", "iban"},
+		},
 		Tags: map[string]types.TagsMeta{
 			"cut": {
 				Desc:          "Task has the potential to save time or cost in the future",
@@ -108,8 +217,12 @@ func CreateDefaultConfig(configPath string) (*types.Config, error) {
 			},
 		},
 	}
+}
+
+func CreateDefaultConfig(configPath string) (*types.Config, error) {
+	config := Defaults()
 
-	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
 		return nil, err
 	}
 
@@ -118,7 +231,8 @@ func CreateDefaultConfig(configPath string) (*types.Config, error) {
 		return nil, err
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	// The config may hold an API key
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
 		return nil, err
 	}
 
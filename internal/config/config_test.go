@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFileKeepsProviderEndpoint(t *testing.T) {
+	for _, content := range []string{
+		"config_version: 2\nllm:\n  provider: deepseek\n  model: deepseek-chat\n",
+		"config_version: 2\nllm:\n  provider: openai\n",
+	} {
+		path := filepath.Join(t.TempDir(), "vanguardrc.yaml")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.LLM.BaseURL != "" {
+			t.Errorf("%q: base_url = %q, want the provider's own endpoint", content, cfg.LLM.BaseURL)
+		}
+	}
+}
+
+func TestLoadFileAddsNoPresets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vanguardrc.yaml")
+	if err := os.WriteFile(path, []byte("config_version: 2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Presets) != 0 {
+		t.Errorf("presets = %v, want none unless configured", cfg.Presets)
+	}
+}
+
+func TestLoadFileMergesEntryMaps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vanguardrc.yaml")
+	content := "config_version: 2\ntags:\n  release:\n    desc: Blocks the release\n    urgency_factor: 1.4\nsettings:\n  debug: true\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Tags["release"]; !ok || len(cfg.Tags) != 1 {
+		t.Errorf("tags = %v, want only release replacing the defaults", cfg.Tags)
+	}
+	if !cfg.Settings.Debug || cfg.Settings.TaskImportLimit != Defaults().Settings.TaskImportLimit {
+		t.Errorf("settings were not merged over the defaults: %+v", cfg.Settings)
+	}
+}
@@ -0,0 +1,383 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// KeyType returns the Go type behind a dotted config key like settings.task_import_limit or tags.cut.desc
+func KeyType(key string) (reflect.Type, error) {
+	t := reflect.TypeOf(types.Config{})
+	for _, part := range strings.Split(key, ".") {
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByYAMLName(t, part)
+			if !ok {
+				return nil, fmt.Errorf("unknown config key %q", key)
+			}
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("unknown config key %q: %s is not a section", key, part)
+		}
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return t, nil
+}
+
+func fieldByYAMLName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.Split(field.Tag.Get("yaml"), ",")[0] == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// parseValue converts a command line value into a YAML node of the type behind key.
+// Lists can be given comma separated or in YAML flow style, sections in YAML flow style.
+func parseValue(key, value string) (*yaml.Node, error) {
+	t, err := KeyType(key)
+	if err != nil {
+		return nil, err
+	}
+
+	target := reflect.New(t)
+	switch t.Kind() {
+	case reflect.String:
+		target.Elem().SetString(value)
+	case reflect.Slice:
+		if !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			var items []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			value = "[" + strings.Join(quoteAll(items), ", ") + "]"
+		}
+		fallthrough
+	default:
+		if err := yaml.Unmarshal([]byte(value), target.Interface()); err != nil {
+			return nil, fmt.Errorf("invalid value for %s (%s): %v", key, t.Kind(), err)
+		}
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(target.Elem().Interface()); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func quoteAll(items []string) []string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	return quoted
+}
+
+// SetKey sets a dotted key in the config file, keeping comments and the order of the other keys.
+// The file is only written if the result is valid.
+func SetKey(configPath, key, value string) error {
+	valueNode, err := parseValue(key, value)
+	if err != nil {
+		return err
+	}
+
+	doc, err := readNode(configPath)
+	if err != nil {
+		return err
+	}
+
+	mapping := doc.Content[0]
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child := lookupNode(mapping, part)
+		if child == nil || child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setNode(mapping, part, child)
+		}
+		mapping = child
+	}
+	setNode(mapping, parts[len(parts)-1], valueNode)
+
+	return writeNode(configPath, doc)
+}
+
+// UnsetKey removes a dotted key from the config file so the default applies again
+func UnsetKey(configPath, key string) error {
+	if _, err := KeyType(key); err != nil {
+		return err
+	}
+
+	doc, err := readNode(configPath)
+	if err != nil {
+		return err
+	}
+
+	mapping := doc.Content[0]
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		mapping = lookupNode(mapping, part)
+		if mapping == nil || mapping.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not set in %s", key, configPath)
+		}
+	}
+	if !deleteNode(mapping, parts[len(parts)-1]) {
+		return fmt.Errorf("%s is not set in %s", key, configPath)
+	}
+
+	return writeNode(configPath, doc)
+}
+
+func readNode(configPath string) (*yaml.Node, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: top level must be a mapping", configPath)
+	}
+	return doc, nil
+}
+
+// writeNode validates the edited document like Load would and writes it
+func writeNode(configPath string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	encoder.Close()
+
+	validate := ValidateData
+	if filepath.Base(configPath) == ProjectFileName {
+		validate = ValidateProjectData
+	}
+	if err := validate(buf.Bytes()); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, buf.Bytes(), 0600)
+}
+
+// ValidateProjectData is ValidateData for a project config, which may not set every key
+func ValidateProjectData(data []byte) error {
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := CheckProjectLayer(raw); err != nil {
+		return err
+	}
+	return ValidateData(data)
+}
+
+// ValidateData checks the content of a config file without writing or migrating anything
+func ValidateData(data []byte) error {
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if _, err := Migrate(raw); err != nil {
+		return err
+	}
+
+	cfg := Defaults()
+	if err := mergeLayer(cfg, raw, make(map[string]bool)); err != nil {
+		return err
+	}
+	return Validate(cfg)
+}
+
+func lookupNode(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setNode(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func deleteNode(mapping *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetKey returns the value of a dotted key from a config
+func GetKey(cfg *types.Config, key string) (interface{}, error) {
+	if _, err := KeyType(key); err != nil {
+		return nil, err
+	}
+
+	var value interface{} = toRaw(cfg)
+	for _, part := range strings.Split(key, ".") {
+		section, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not set", key)
+		}
+		if value, ok = section[part]; !ok {
+			return nil, fmt.Errorf("%s is not set", key)
+		}
+	}
+	return value, nil
+}
+
+// toRaw converts a config into the generic form a YAML file decodes into
+func toRaw(cfg *types.Config) map[string]interface{} {
+	raw := make(map[string]interface{})
+	data, err := yaml.Marshal(cfg)
+	if err == nil {
+		yaml.Unmarshal(data, &raw)
+	}
+	return raw
+}
+
+// Flatten turns a generic config into dotted keys. Lists and empty sections are kept as single values.
+func Flatten(raw map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	flattenInto(flat, "", raw)
+	return flat
+}
+
+func flattenInto(flat map[string]interface{}, prefix string, raw map[string]interface{}) {
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if section, ok := value.(map[string]interface{}); ok && len(section) > 0 {
+			flattenInto(flat, key, section)
+			continue
+		}
+		flat[key] = value
+	}
+}
+
+// FormatValue renders a config value on a single line
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		var buf bytes.Buffer
+		node := &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return fmt.Sprint(v)
+		}
+		setFlowStyle(node)
+		encoder := yaml.NewEncoder(&buf)
+		encoder.Encode(node)
+		encoder.Close()
+		return strings.TrimSpace(buf.String())
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func setFlowStyle(node *yaml.Node) {
+	node.Style |= yaml.FlowStyle
+	for _, child := range node.Content {
+		setFlowStyle(child)
+	}
+}
+
+// SortedKeys returns the keys of a flattened config in order
+func SortedKeys(flat map[string]interface{}) []string {
+	return sortedKeys(flat)
+}
+
+// Value is an effective config value and where it came from
+type Value struct {
+	Key    string
+	Value  interface{}
+	Source string // default, system, user, project, flag, env <name>, llm.commands.<command>, api_key_cmd or credentials file
+}
+
+// Effective returns every key of the config a command runs with: the merged config layers,
+// the llm.commands block of command and the --provider/--model flags in cli, plus the API key
+// resolved from its sources. command may be empty to show the base settings.
+func Effective(loaded *Loaded, command string, cli types.LLMOverride) ([]Value, error) {
+	copied := *loaded.Config
+	cfg := &copied
+
+	base := Flatten(map[string]interface{}{"llm": toRaw(&types.Config{LLM: cfg.LLM})["llm"]})
+	commandLLM := cfg.LLM.ForCommand(command)
+	withCommand := Flatten(map[string]interface{}{"llm": toRaw(&types.Config{LLM: commandLLM})["llm"]})
+	cfg.LLM.CLI = cli
+	cfg.LLM = cfg.LLM.ForCommand(command)
+	if err := cfg.LLM.CheckModel(); err != nil {
+		return nil, err
+	}
+
+	flat := Flatten(toRaw(cfg))
+	values := make([]Value, 0, len(flat))
+	for _, key := range SortedKeys(flat) {
+		value := Value{Key: key, Value: flat[key], Source: "default"}
+		if source, ok := loaded.Sources[key]; ok {
+			value.Source = source
+		}
+		if strings.HasPrefix(key, "llm.") {
+			switch formatted := FormatValue(flat[key]); {
+			case formatted != FormatValue(withCommand[key]):
+				value.Source = "flag"
+			case formatted != FormatValue(base[key]):
+				value.Source = "llm.commands." + command
+			}
+		}
+		values = append(values, value)
+	}
+
+	key, source, err := ResolveAPIKeySource(cfg.LLM)
+	if err != nil {
+		return nil, err
+	}
+	for i := range values {
+		values[i].Value = MaskSecrets(values[i].Key, values[i].Value)
+		if values[i].Key == "llm.api_key" && key != "" {
+			values[i].Value = MaskKey(key)
+			if source != "api_key" {
+				values[i].Source = source
+			}
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// SystemDir holds the config shared by all users of the machine
+var SystemDir = "/etc/taskvanguard"
+
+// ProjectFileName is searched from the working directory upwards, the nearest file applies
+const ProjectFileName = ".vanguardrc.yaml"
+
+// projectDenied are the sections a project config may not set. A .vanguardrc.yaml may come with a cloned
+// repository, it must not run api_key_cmd, send the key or the tasks elsewhere or switch off the privacy filters.
+var projectDenied = []string{"llm", "filters", "redaction"}
+
+// EnvPrefix starts the environment variables overriding single keys, e.g. TASKVANGUARD_LLM_MODEL
+const EnvPrefix = "TASKVANGUARD_"
+
+// Layer is a config file merged over the defaults
+type Layer struct {
+	Name string // system, user or project
+	Path string
+}
+
+// Loaded is a merged config together with the layers it was built from
+type Loaded struct {
+	Config  *types.Config
+	Layers  []Layer
+	Sources map[string]string // dotted key -> layer name or "env <variable>", keys missing here are defaults
+}
+
+// Describe lists the files a config was loaded from, for error messages
+func (l *Loaded) Describe() string {
+	paths := make([]string, 0, len(l.Layers))
+	for _, layer := range l.Layers {
+		paths = append(paths, layer.Path)
+	}
+	if len(paths) == 0 {
+		return "defaults"
+	}
+	return strings.Join(paths, ", ")
+}
+
+// Layers returns the existing config files in the order they are merged:
+// the system config, the user config and the nearest project config
+func Layers() ([]Layer, error) {
+	userPath, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	var layers []Layer
+	for _, layer := range []Layer{
+		{Name: "system", Path: filepath.Join(SystemDir, "vanguardrc.yaml")},
+		{Name: "user", Path: userPath},
+	} {
+		if _, err := os.Stat(layer.Path); err == nil {
+			layers = append(layers, layer)
+		}
+	}
+
+	projectPath, err := ProjectPath()
+	if err != nil {
+		return nil, err
+	}
+	if projectPath != "" {
+		layers = append(layers, Layer{Name: "project", Path: projectPath})
+	}
+	return layers, nil
+}
+
+// ProjectPath returns the nearest .vanguardrc.yaml in the working directory or one of its parents, or "" if there is none
+func ProjectPath() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadLayers merges the defaults, every config layer and the TASKVANGUARD_* variables. The result is not validated.
+//
+// Settings are merged key by key and lists are replaced. tags, annotations and presets are merged by entry:
+// the first layer defining one of them replaces the default entries, later layers add or replace single
+// entries and remove them with a null value, e.g. "tags: {cut: null}".
+func LoadLayers() (*Loaded, error) {
+	layers, err := Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := &Loaded{Config: Defaults(), Layers: layers, Sources: make(map[string]string)}
+	replaced := make(map[string]bool)
+	for _, layer := range layers {
+		raw, err := readLayer(layer)
+		if err != nil {
+			return nil, err
+		}
+		if layer.Name == "project" {
+			if err := CheckProjectLayer(raw); err != nil {
+				return nil, fmt.Errorf("%s: %w", layer.Path, err)
+			}
+		}
+		if err := mergeLayer(loaded.Config, raw, replaced); err != nil {
+			return nil, fmt.Errorf("%s: %w", layer.Path, err)
+		}
+		for key := range Flatten(raw) {
+			loaded.Sources[key] = layer.Name
+		}
+	}
+
+	raw, sources, err := envOverrides()
+	if err != nil {
+		return nil, err
+	}
+	if err := mergeLayer(loaded.Config, raw, replaced); err != nil {
+		return nil, err
+	}
+	for key, source := range sources {
+		loaded.Sources[key] = source
+	}
+	return loaded, nil
+}
+
+// CheckProjectLayer rejects a project config setting keys that only the user and system config may set
+func CheckProjectLayer(raw map[string]interface{}) error {
+	var denied []string
+	for key := range Flatten(raw) {
+		parts := strings.Split(key, ".")
+		if slices.Contains(projectDenied, parts[0]) || strings.HasPrefix(parts[len(parts)-1], "api_key") {
+			denied = append(denied, key)
+		}
+	}
+	if len(denied) == 0 {
+		return nil
+	}
+	sort.Strings(denied)
+	return fmt.Errorf("%s can only be set in the user or system config, not in %s", strings.Join(denied, ", "), ProjectFileName)
+}
+
+// readLayer reads and migrates a config file. Only the user config is rewritten,
+// system and project files may be shared or checked in and are migrated in memory.
+func readLayer(layer Layer) (map[string]interface{}, error) {
+	raw, err := readRaw(layer.Path)
+	if err != nil {
+		return nil, err
+	}
+	if layer.Name == "user" {
+		if err := restrictKeyFile(layer.Path, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	applied, err := Migrate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", layer.Path, err)
+	}
+	if len(applied) == 0 {
+		return raw, nil
+	}
+
+	if layer.Name == "user" {
+		backupPath, err := writeMigrated(layer.Path, raw)
+		if err != nil {
+			return nil, fmt.Errorf("save migrated config: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Migrated %s to config version %d (backup: %s):\n", layer.Path, CurrentVersion, backupPath)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s is config version %d or older, update it to version %d:\n", layer.Path, CurrentVersion-1, CurrentVersion)
+	}
+	for _, description := range applied {
+		fmt.Fprintf(os.Stderr, "  - %s\n", description)
+	}
+	return raw, nil
+}
+
+// EnvKeys maps the variables overriding single keys to dotted keys, e.g. TASKVANGUARD_SETTINGS_TASK_IMPORT_LIMIT
+// to settings.task_import_limit. Entries of maps like tags or llm.commands can not be set this way.
+func EnvKeys() map[string]string {
+	keys := make(map[string]string)
+	collectEnvKeys(reflect.TypeOf(types.Config{}), "", keys)
+	return keys
+}
+
+func collectEnvKeys(t reflect.Type, prefix string, keys map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" || name == "config_version" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch fieldType.Kind() {
+		case reflect.Struct:
+			collectEnvKeys(fieldType, name, keys)
+		case reflect.Map:
+		default:
+			keys[EnvPrefix+strings.ToUpper(strings.ReplaceAll(name, ".", "_"))] = name
+		}
+	}
+}
+
+// envOverrides builds a raw config layer from the TASKVANGUARD_* variables that are set and not empty
+func envOverrides() (map[string]interface{}, map[string]string, error) {
+	raw := make(map[string]interface{})
+	sources := make(map[string]string)
+	keys := EnvKeys()
+	for _, env := range sortedKeys(keys) {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+
+		key := keys[env]
+		node, err := parseValue(key, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", env, err)
+		}
+		var decoded interface{}
+		if err := node.Decode(&decoded); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", env, err)
+		}
+
+		section := raw
+		parts := strings.Split(key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := section[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				section[part] = child
+			}
+			section = child
+		}
+		section[parts[len(parts)-1]] = decoded
+		sources[key] = "env " + env
+	}
+	return raw, sources, nil
+}
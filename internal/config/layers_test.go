@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupLayers points the system and user config into a temp dir and changes into a project directory below it
+func setupLayers(t *testing.T, user, project string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	oldSystemDir := SystemDir
+	SystemDir = filepath.Join(dir, "etc")
+	t.Cleanup(func() { SystemDir = oldSystemDir })
+
+	userPath := filepath.Join(dir, "vanguardrc.yaml")
+	if err := os.WriteFile(userPath, []byte(user), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TASKVANGUARD_CONFIG", userPath)
+
+	projectDir := filepath.Join(dir, "repo", "sub")
+	if err := os.MkdirAll(projectDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if project != "" {
+		if err := os.WriteFile(filepath.Join(dir, "repo", ProjectFileName), []byte(project), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(projectDir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestLoadLayersMergesProjectConfig(t *testing.T) {
+	setupLayers(t,
+		"config_version: 2\nsettings:\n  task_import_limit: 50\n  debug: true\n",
+		"config_version: 2\nsettings:\n  task_import_limit: 10\n")
+
+	loaded, err := LoadLayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Config.Settings.TaskImportLimit; got != 10 {
+		t.Errorf("task_import_limit = %d, want 10 from the project config", got)
+	}
+	if !loaded.Config.Settings.Debug {
+		t.Error("debug from the user config was lost")
+	}
+	if got := loaded.Sources["settings.task_import_limit"]; got != "project" {
+		t.Errorf("source = %q, want project", got)
+	}
+}
+
+func TestLoadLayersRejectsPrivilegedProjectKeys(t *testing.T) {
+	for _, project := range []string{
+		"llm:\n  api_key_cmd: touch /tmp/pwned\n",
+		"llm:\n  base_url: https://example.com\n",
+		"llm:\n  commands:\n    spot:\n      provider: ollama\n",
+		"filters:\n  tag_filter_mode: whitelist\n",
+		"redaction:\n  enabled: false\n",
+	} {
+		setupLayers(t, "config_version: 2\n", "config_version: 2\n"+project)
+
+		_, err := LoadLayers()
+		if err == nil || !strings.Contains(err.Error(), "can only be set in the user or system config") {
+			t.Errorf("project config %q: err = %v, want rejection", project, err)
+		}
+	}
+}
+
+func TestSetKeyRejectsPrivilegedProjectKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ProjectFileName)
+	if err := os.WriteFile(path, []byte("config_version: 2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetKey(path, "llm.model", "gpt-4.1"); err == nil {
+		t.Error("llm.model was set in the project config")
+	}
+	if err := SetKey(path, "settings.task_import_limit", "20"); err != nil {
+		t.Errorf("settings.task_import_limit: %v", err)
+	}
+}
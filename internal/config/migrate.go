@@ -0,0 +1,71 @@
+package config
+
+import "fmt"
+
+// CurrentVersion is the config_version written by this release. Files without config_version are version 1.
+const CurrentVersion = 2
+
+type migration struct {
+	to          int
+	description string
+	apply       func(raw map[string]interface{})
+}
+
+// migrations upgrade raw config files step by step, ordered by target version
+var migrations = []migration{
+	{
+		to:          2,
+		description: "removed zero settings that meant 'use the default' and placeholder API keys",
+		apply: func(raw map[string]interface{}) {
+			if settings, ok := raw["settings"].(map[string]interface{}); ok {
+				for _, key := range []string{"task_import_limit", "task_processing_batch_size", "guiding_question_amount", "context_ttl_minutes"} {
+					if value, ok := settings[key].(int); ok && value <= 0 {
+						delete(settings, key)
+					}
+				}
+			}
+			if llm, ok := raw["llm"].(map[string]interface{}); ok {
+				if key, ok := llm["api_key"].(string); ok && isPlaceholderKey(key) {
+					delete(llm, "api_key")
+				}
+			}
+		},
+	},
+}
+
+// Migrate upgrades a raw config in place to CurrentVersion and returns the descriptions of the applied steps
+func Migrate(raw map[string]interface{}) ([]string, error) {
+	version, err := rawVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("config_version %d is newer than this version of TaskVanguard supports (%d), please upgrade", version, CurrentVersion)
+	}
+
+	var applied []string
+	for _, m := range migrations {
+		if m.to <= version {
+			continue
+		}
+		m.apply(raw)
+		applied = append(applied, fmt.Sprintf("v%d: %s", m.to, m.description))
+	}
+
+	if version != CurrentVersion {
+		raw["config_version"] = CurrentVersion
+	}
+	return applied, nil
+}
+
+func rawVersion(raw map[string]interface{}) (int, error) {
+	value, ok := raw["config_version"]
+	if !ok || value == nil {
+		return 1, nil
+	}
+	version, ok := value.(int)
+	if !ok || version < 1 {
+		return 0, fmt.Errorf("config_version must be a positive number, got %v", value)
+	}
+	return version, nil
+}
@@ -0,0 +1,281 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// APIKeyEnv overrides the API key of the configured provider from every other source.
+// Commands and templates switching to another provider use ProviderAPIKeyEnv instead.
+const APIKeyEnv = "TASKVANGUARD_API_KEY"
+
+// cmdKeys caches the output of api_key_cmd, so a password manager is only asked once per run
+var cmdKeys = make(map[string]string)
+
+// ProviderAPIKeyEnv returns the variable holding the key of one provider, e.g. TASKVANGUARD_DEEPSEEK_API_KEY
+func ProviderAPIKeyEnv(provider string) string {
+	return EnvPrefix + strings.ToUpper(provider) + "_API_KEY"
+}
+
+// CredentialsPath returns the location of the credentials file next to the config file.
+// It maps provider names (or "default") to API keys and must only be readable by the user.
+func CredentialsPath() (string, error) {
+	if envPath := os.Getenv("TASKVANGUARD_CREDENTIALS"); envPath != "" {
+		return envPath, nil
+	}
+	configPath, err := Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "credentials.yaml"), nil
+}
+
+// ResolveAPIKey returns the API key for the given LLM settings. Sources in order:
+// TASKVANGUARD_<PROVIDER>_API_KEY, TASKVANGUARD_API_KEY if the settings use the base provider,
+// the variable named in api_key_env, the output of api_key_cmd, api_key from the config and
+// finally the credentials file. An empty key means none is configured.
+func ResolveAPIKey(cfg types.LLMConfig) (string, error) {
+	key, _, err := ResolveAPIKeySource(cfg)
+	return key, err
+}
+
+// ResolveAPIKeySource is ResolveAPIKey that also names the source the key came from
+func ResolveAPIKeySource(cfg types.LLMConfig) (key string, source string, err error) {
+	if key, source := envAPIKey(cfg); key != "" {
+		return key, source, nil
+	}
+
+	if cfg.APIKeyCmd != "" {
+		if key, ok := cmdKeys[cfg.APIKeyCmd]; ok {
+			return key, "api_key_cmd", nil
+		}
+		cmd := exec.Command("sh", "-c", cfg.APIKeyCmd)
+		cmd.Stdin = os.Stdin // password managers may ask for a passphrase
+		cmd.Stderr = os.Stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return "", "", fmt.Errorf("api_key_cmd failed: %v", err)
+		}
+		// Tools like pass print the secret on the first line and metadata after it
+		key := strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0])
+		if key == "" {
+			return "", "", fmt.Errorf("api_key_cmd returned no key")
+		}
+		cmdKeys[cfg.APIKeyCmd] = key
+		return key, "api_key_cmd", nil
+	}
+
+	if !isPlaceholderKey(cfg.APIKey) {
+		return cfg.APIKey, "api_key", nil
+	}
+
+	key, err = credentialsKey(cfg.Provider)
+	if key == "" || err != nil {
+		return key, "", err
+	}
+	return key, "credentials file", nil
+}
+
+// CheckAPIKey fails with MissingKeyError if no key source is configured for the given LLM settings.
+// Commands call it before changing anything, api_key_cmd is not run until the first prompt is sent.
+func CheckAPIKey(cfg types.LLMConfig) error {
+	// A local ollama model does not need a key
+	if cfg.Provider == "ollama" {
+		return nil
+	}
+	if key, _ := envAPIKey(cfg); key != "" || cfg.APIKeyCmd != "" || !isPlaceholderKey(cfg.APIKey) {
+		return nil
+	}
+	key, err := credentialsKey(cfg.Provider)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return MissingKeyError(cfg.Provider)
+	}
+	return nil
+}
+
+// envAPIKey returns the key from the first variable set for the given LLM settings and names the variable
+func envAPIKey(cfg types.LLMConfig) (string, string) {
+	var names []string
+	if cfg.Provider != "" {
+		names = append(names, ProviderAPIKeyEnv(cfg.Provider))
+	}
+	if cfg.BaseProvider == "" || cfg.BaseProvider == cfg.Provider {
+		names = append(names, APIKeyEnv)
+	}
+	if cfg.APIKeyEnv != "" {
+		names = append(names, cfg.APIKeyEnv)
+	}
+
+	for _, name := range names {
+		if key := strings.TrimSpace(os.Getenv(name)); key != "" {
+			return key, "env " + name
+		}
+	}
+	return "", ""
+}
+
+// restrictKeyFile makes a config file holding an API key readable for the owner only,
+// files written by older versions are world readable
+func restrictKeyFile(path string, raw map[string]interface{}) error {
+	hasKey := false
+	for key, value := range Flatten(raw) {
+		if strings.HasSuffix(key, ".api_key") {
+			if s, ok := value.(string); ok && !isPlaceholderKey(s) {
+				hasKey = true
+			}
+		}
+	}
+	if !hasKey {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0077 == 0 {
+		return nil
+	}
+	if err := os.Chmod(path, info.Mode().Perm()&0700); err != nil {
+		return fmt.Errorf("%s contains an API key and is readable by other users: %v", path, err)
+	}
+	fmt.Fprintf(os.Stderr, "%s contains an API key, made it readable for your user only\n", path)
+	return nil
+}
+
+// MissingKeyError explains where to configure the key for a provider
+func MissingKeyError(provider string) error {
+	credentialsPath, _ := CredentialsPath()
+	return fmt.Errorf("LLM API key for %s not configured. Set %s, api_key_env, api_key_cmd or api_key in the config, or add it to %s", provider, APIKeyEnv, credentialsPath)
+}
+
+// MaskKey hides all but the ends of an API key for display
+func MaskKey(key string) string {
+	if len(key) <= 12 {
+		return strings.Repeat("*", len(key))
+	}
+	return key[:3] + "..." + key[len(key)-4:]
+}
+
+// IsSecretKey reports whether a dotted config key holds an API key, which is never printed in clear text
+func IsSecretKey(key string) bool {
+	parts := strings.Split(key, ".")
+	return parts[0] == "llm" && parts[len(parts)-1] == "api_key"
+}
+
+// MaskSecrets returns the value of key with every API key in it masked, including those in nested sections
+func MaskSecrets(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for name, item := range v {
+			childKey := name
+			if key != "" {
+				childKey = key + "." + name
+			}
+			masked[name] = MaskSecrets(childKey, item)
+		}
+		return masked
+	case string:
+		if IsSecretKey(key) && v != "" {
+			return MaskKey(v)
+		}
+	}
+	return value
+}
+
+// MaskFile returns the content of a config file with its API keys masked.
+// Files without keys are returned unchanged, others are re-encoded with their comments.
+func MaskFile(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if !maskNode(&doc, "") {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	encoder.Close()
+	return buf.Bytes(), nil
+}
+
+// maskNode masks the API keys below node, key is the dotted key of node, and reports whether it changed anything
+func maskNode(node *yaml.Node, key string) bool {
+	changed := false
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			changed = maskNode(child, key) || changed
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childKey := node.Content[i].Value
+			if key != "" {
+				childKey = key + "." + childKey
+			}
+			value := node.Content[i+1]
+			if value.Kind == yaml.ScalarNode && IsSecretKey(childKey) && value.Value != "" {
+				value.Value = MaskKey(value.Value)
+				changed = true
+				continue
+			}
+			changed = maskNode(value, childKey) || changed
+		}
+	}
+	return changed
+}
+
+// isPlaceholderKey reports whether the key is empty or one of the placeholders older default configs contained
+func isPlaceholderKey(key string) bool {
+	key = strings.TrimSpace(key)
+	return key == "" || key == "YOUR_API_KEY_HERE" || key == "<YOUR_API_KEY_HERE>" || key == "<api key>"
+}
+
+func credentialsKey(provider string) (string, error) {
+	path, err := CredentialsPath()
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("credentials file %s is accessible by other users, run: chmod 600 %s", path, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var keys map[string]string
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return "", fmt.Errorf("parse credentials file %s: %v", path, err)
+	}
+
+	if key := strings.TrimSpace(keys[provider]); key != "" {
+		return key, nil
+	}
+	return strings.TrimSpace(keys["default"]), nil
+}
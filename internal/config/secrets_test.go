@@ -0,0 +1,196 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+func TestResolveAPIKeyEnvOnlyForBaseProvider(t *testing.T) {
+	t.Setenv(APIKeyEnv, "base-key")
+	t.Setenv("TASKVANGUARD_CREDENTIALS", filepath.Join(t.TempDir(), "missing.yaml"))
+
+	cfg := types.LLMConfig{
+		Provider: "openai",
+		Commands: map[string]types.LLMOverride{"spot": {Provider: "deepseek"}},
+	}
+
+	key, err := ResolveAPIKey(cfg.ForCommand("add"))
+	if err != nil || key != "base-key" {
+		t.Errorf("base provider: key = %q, %v, want base-key", key, err)
+	}
+
+	key, err = ResolveAPIKey(cfg.ForCommand("spot"))
+	if err != nil || key != "" {
+		t.Errorf("switched provider: key = %q, %v, want none", key, err)
+	}
+
+	t.Setenv(ProviderAPIKeyEnv("deepseek"), "deepseek-key")
+	key, err = ResolveAPIKey(cfg.ForCommand("spot"))
+	if err != nil || key != "deepseek-key" {
+		t.Errorf("switched provider: key = %q, %v, want deepseek-key", key, err)
+	}
+}
+
+func TestCheckAPIKey(t *testing.T) {
+	t.Setenv(APIKeyEnv, "")
+	t.Setenv(ProviderAPIKeyEnv("openai"), "")
+	credentials := filepath.Join(t.TempDir(), "credentials.yaml")
+	t.Setenv("TASKVANGUARD_CREDENTIALS", credentials)
+
+	cfg := types.LLMConfig{Provider: "openai", APIKey: "YOUR_API_KEY_HERE"}
+	if err := CheckAPIKey(cfg); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("no key source: got %v", err)
+	}
+
+	// The command is only run when a prompt is sent
+	withCmd := cfg
+	withCmd.APIKeyCmd = "exit 1"
+	if err := CheckAPIKey(withCmd); err != nil {
+		t.Errorf("api_key_cmd: got %v", err)
+	}
+
+	if err := os.WriteFile(credentials, []byte("openai: sk-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckAPIKey(cfg); err != nil {
+		t.Errorf("credentials file: got %v", err)
+	}
+
+	if err := CheckAPIKey(types.LLMConfig{Provider: "ollama"}); err != nil {
+		t.Errorf("ollama: got %v", err)
+	}
+}
+
+func TestResolveAPIKeyRunsCommandOnce(t *testing.T) {
+	t.Setenv(APIKeyEnv, "")
+	counter := filepath.Join(t.TempDir(), "runs")
+	cfg := types.LLMConfig{Provider: "openai", APIKeyCmd: "echo run >> " + counter + "; echo secret; echo metadata"}
+
+	for i := 0; i < 2; i++ {
+		key, err := ResolveAPIKey(cfg)
+		if err != nil || key != "secret" {
+			t.Fatalf("key = %q, %v, want secret", key, err)
+		}
+	}
+	runs, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(runs) != "run\n" {
+		t.Errorf("api_key_cmd ran %q, want once", runs)
+	}
+}
+
+func TestRestrictKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	withKey := filepath.Join(dir, "with-key.yaml")
+	withoutKey := filepath.Join(dir, "without-key.yaml")
+	for path, content := range map[string]string{
+		withKey:    "llm:\n  api_key: sk-123\n",
+		withoutKey: "llm:\n  api_key: YOUR_API_KEY_HERE\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, 0644); err != nil {
+			t.Fatal(err)
+		}
+		raw, err := readRaw(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := restrictKeyFile(path, raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for path, want := range map[string]os.FileMode{withKey: 0600, withoutKey: 0644} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != want {
+			t.Errorf("%s: mode %o, want %o", filepath.Base(path), info.Mode().Perm(), want)
+		}
+	}
+}
+
+const secretConfig = `config_version: 2
+llm:
+    provider: openai
+    model: gpt-4.1
+    # personal key
+    api_key: sk-live-1234567890abcdef
+    templates:
+        spotlight.md:
+            provider: deepseek
+            model: deepseek-chat
+            api_key: "ds-0987654321fedcba"
+`
+
+func TestGetKeyMasksSecrets(t *testing.T) {
+	setupLayers(t, secretConfig, "")
+	loaded, err := LoadLayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"llm.api_key", "llm", "llm.templates"} {
+		value, err := GetKey(loaded.Config, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := FormatValue(MaskSecrets(key, value))
+		if strings.Contains(out, "1234567890") || strings.Contains(out, "0987654321") {
+			t.Errorf("config get %s prints a key: %s", key, out)
+		}
+	}
+
+	value, _ := GetKey(loaded.Config, "llm.model")
+	if got := FormatValue(MaskSecrets("llm.model", value)); got != "gpt-4.1" {
+		t.Errorf("llm.model = %q", got)
+	}
+}
+
+func TestEffectiveMasksSecrets(t *testing.T) {
+	setupLayers(t, secretConfig, "")
+	loaded, err := LoadLayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := Effective(loaded, "spot", types.LLMOverride{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range values {
+		if out := FormatValue(value.Value); strings.Contains(out, "1234567890") || strings.Contains(out, "0987654321") {
+			t.Errorf("%s prints a key: %s", value.Key, out)
+		}
+	}
+}
+
+func TestMaskFile(t *testing.T) {
+	masked, err := MaskFile([]byte(secretConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(masked)
+	if strings.Contains(out, "1234567890") || strings.Contains(out, "0987654321") {
+		t.Errorf("masked file still holds a key:\n%s", out)
+	}
+	for _, want := range []string{"# personal key", "api_key: sk-...cdef", "model: deepseek-chat"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("masked file lacks %q:\n%s", want, out)
+		}
+	}
+
+	plain := "config_version: 2\nllm:\n  model: gpt-4.1 # keep my layout\n"
+	if unchanged, err := MaskFile([]byte(plain)); err != nil || string(unchanged) != plain {
+		t.Errorf("file without keys changed to %q (%v)", unchanged, err)
+	}
+}
@@ -1,54 +1,139 @@
 package config
 
-// "os"
-// "path/filepath"
-
-// func TemplatesDir() (string, error) {
-// 	configDir, err := os.UserConfigDir()
-// 	if err != nil {
-// 		return "", err
-// 	}
-// 	return filepath.Join(configDir, "taskvanguard", "templates"), nil
-// }
-
-// func UserTemplatePath(filename string) (string, error) {
-// 	dir, err := TemplatesDir()
-// 	if err != nil {
-// 		return "", err
-// 	}
-// 	return filepath.Join(dir, filename), nil
-// }
-
-// func TemplateExists(filename string) bool {
-// 	path, err := UserTemplatePath(filename)
-// 	if err != nil {
-// 		return false
-// 	}
-// 	_, err = os.Stat(path)
-// 	return err == nil
-// }
-
-// func EnsureTemplate(filename string, content string) error {
-// 	path, err := UserTemplatePath(filename)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	if _, err := os.Stat(path); err == nil {
-// 		return nil // already exists
-// 	}
-
-// 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-// 		return err
-// 	}
-
-// 	return os.WriteFile(path, []byte(content), 0644)
-// }
-
-// func ReadDefaultTemplate(filename string) (string, error) {
-// 	data, err := Templates.ReadFile("templates/" + filename)
-// 	if err != nil {
-// 		return "", err
-// 	}
-// 	return string(data), nil
-// }
\ No newline at end of file
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/taskvanguard/taskvanguard/assets"
+)
+
+// RetiredTemplates maps templates that are no longer used to the template that replaced them,
+// overrides of them are ignored
+var RetiredTemplates = map[string]string{
+	"task_analysis.md": "task_analysis_single.md",
+}
+
+// TemplatesDir returns the directory of user prompt templates overriding the embedded ones,
+// next to the config file unless TASKVANGUARD_TEMPLATES is set
+func TemplatesDir() (string, error) {
+	if envPath := os.Getenv("TASKVANGUARD_TEMPLATES"); envPath != "" {
+		return envPath, nil
+	}
+	configPath, err := Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "templates"), nil
+}
+
+// UserTemplatePath returns where the override of a template is looked for
+func UserTemplatePath(filename string) (string, error) {
+	dir, err := TemplatesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(filename)), nil
+}
+
+// TemplateExists reports whether the user overrides a template
+func TemplateExists(filename string) bool {
+	path, err := UserTemplatePath(filename)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// ReadUserTemplate returns the override of a template, ok is false if there is none
+func ReadUserTemplate(filename string) (content string, path string, ok bool, err error) {
+	path, err = UserTemplatePath(filename)
+	if err != nil {
+		return "", "", false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", path, false, nil
+	}
+	if err != nil {
+		return "", path, false, err
+	}
+	return string(data), path, true, nil
+}
+
+// ReadDefaultTemplate returns the embedded version of a template
+func ReadDefaultTemplate(filename string) (string, error) {
+	return assets.Load(filename)
+}
+
+// DefaultTemplateNames lists the embedded templates
+func DefaultTemplateNames() ([]string, error) {
+	entries, err := fs.ReadDir(assets.Templates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// UserTemplateNames lists the files in the templates directory, including ones not matching an embedded template
+func UserTemplateNames() ([]string, error) {
+	dir, err := TemplatesDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ExportTemplate writes the embedded version of a template to the templates directory to customize it.
+// An existing override is only replaced if overwrite is set.
+func ExportTemplate(filename string, overwrite bool) (string, error) {
+	content, err := ReadDefaultTemplate(filename)
+	if err != nil {
+		return "", err
+	}
+	path, err := UserTemplatePath(filename)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return path, fs.ErrExist
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte(content), 0600)
+}
+
+// ResetTemplate removes the override of a template so the embedded version applies again
+func ResetTemplate(filename string) (string, error) {
+	path, err := UserTemplatePath(filename)
+	if err != nil {
+		return "", err
+	}
+	return path, os.Remove(path)
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/pkg/filter"
+	"github.com/taskvanguard/taskvanguard/pkg/redact"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// Providers are the supported values of llm.provider
+var Providers = []string{"openai", "deepseek", "ollama"}
+
+// LLMCommands are the commands talking to the LLM, the valid keys of llm.commands
+var LLMCommands = []string{"add", "analyze", "spot", "guide", "goals align", "goals review"}
+
+// ValidationError lists every problem found in a config, each prefixed with the key it refers to
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// oneOf checks an enum, an empty allowed value means the key may be left out.
+// Values are compared case-sensitively like the code switching on them.
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	var named []string
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+		if a != "" {
+			named = append(named, a)
+		}
+	}
+	v.addf("%s must be one of %s, got %q", key, strings.Join(named, ", "), value)
+}
+
+// name checks a required name that must not contain any of the characters in forbidden, described for the message
+func (v *validator) name(key, value, forbidden, described string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf("%s must not be empty", key)
+	} else if strings.ContainsAny(value, forbidden) {
+		v.addf("%s must not contain %s, got %q", key, described, value)
+	}
+}
+
+func (v *validator) atLeast(key string, value, min int) {
+	if value < min {
+		v.addf("%s must be at least %d, got %d", key, min, value)
+	}
+}
+
+// Validate checks ranges, enums and patterns of a merged config and returns a *ValidationError listing all problems
+func Validate(cfg *types.Config) error {
+	v := &validator{}
+
+	v.validateLLM("llm", types.LLMOverride{
+		Provider:    cfg.LLM.Provider,
+		Model:       cfg.LLM.Model,
+		Temperature: cfg.LLM.Temperature,
+		MaxTokens:   cfg.LLM.MaxTokens,
+	}, true)
+	for _, name := range sortedKeys(cfg.LLM.Commands) {
+		v.validateLLM("llm.commands."+name, cfg.LLM.Commands[name], false)
+		v.modelForProvider("llm.commands."+name, cfg.LLM.Commands[name], cfg.LLM.Provider)
+		if !slices.Contains(LLMCommands, name) {
+			v.addf("llm.commands.%s is not a command using the LLM, expected one of %s", name, strings.Join(LLMCommands, ", "))
+		}
+	}
+	for _, name := range sortedKeys(cfg.LLM.Templates) {
+		v.validateLLM("llm.templates."+name, cfg.LLM.Templates[name], false)
+		v.modelForProvider("llm.templates."+name, cfg.LLM.Templates[name], cfg.LLM.Provider)
+	}
+
+	s := cfg.Settings
+	v.oneOf("settings.goal_mode", s.GoalMode, types.GoalModeProject, types.GoalModeTag, types.GoalModeUDA, "")
+	switch s.GoalMode {
+	case types.GoalModeTag:
+		v.name("settings.goal_tag", s.GoalTag, " \t+", "spaces or +")
+	case types.GoalModeUDA:
+		v.name("settings.goal_uda", s.GoalUDA, " \t:", "spaces or :")
+	default:
+		v.name("settings.goal_project_name", s.GoalProjectName, " \t", "spaces")
+	}
+	v.atLeast("settings.goal_velocity_weeks", s.GoalVelocityWeeks, 1)
+	v.atLeast("settings.goal_review_days", s.GoalReviewDays, 1)
+	v.atLeast("settings.goal_stale_days", s.GoalStaleDays, 1)
+	v.atLeast("settings.goal_history_limit", s.GoalHistoryLimit, 1)
+	v.atLeast("settings.task_import_limit", s.TaskImportLimit, 1)
+	v.atLeast("settings.task_processing_batch_size", s.TaskProcessingBatchSize, 1)
+	v.atLeast("settings.guiding_question_amount", s.GuidingQuestionAmount, 1)
+	v.atLeast("settings.context_ttl_minutes", s.ContextTTLMinutes, 1)
+
+	f := cfg.Filters
+	v.oneOf("filters.tag_filter_mode", f.TagFilterMode, "blacklist", "whitelist", "")
+	v.oneOf("filters.project_filter_mode", f.ProjectFilterMode, "blacklist", "whitelist", "")
+	v.oneOf("filters.filter_combine", f.FilterCombine, "and", "or", "")
+	for i, pattern := range f.TagFilterTags {
+		if err := filter.ValidatePattern(pattern); err != nil {
+			v.addf("filters.tag_filter_tags[%d]: %v", i, err)
+		}
+	}
+	for i, pattern := range f.ProjectFilterProjects {
+		if err := filter.ValidatePattern(pattern); err != nil {
+			v.addf("filters.project_filter_projects[%d]: %v", i, err)
+		}
+	}
+
+	if _, err := redact.New(cfg.Redaction); err != nil {
+		v.addf("redaction: %v", err)
+	}
+
+	for _, name := range sortedKeys(cfg.Presets) {
+		if name == "" || strings.ContainsAny(name, " \t@") {
+			v.addf("presets: name %q must not be empty or contain spaces or @", name)
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Tags) {
+		if strings.ContainsAny(name, " \t+") {
+			v.addf("tags: name %q must not contain spaces or +", name)
+		}
+		if cfg.Tags[name].UrgencyFactor < 0 {
+			v.addf("tags.%s.urgency_factor must not be negative, got %g", name, cfg.Tags[name].UrgencyFactor)
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Annotations) {
+		if strings.TrimSpace(cfg.Annotations[name].Label) == "" {
+			v.addf("annotations.%s.label must not be empty", name)
+		}
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// validateLLM checks LLM settings. Overrides may leave provider and model empty to inherit them.
+func (v *validator) validateLLM(key string, llm types.LLMOverride, required bool) {
+	if llm.Provider != "" || required {
+		v.oneOf(key+".provider", llm.Provider, Providers...)
+	}
+	if required && strings.TrimSpace(llm.Model) == "" {
+		v.addf("%s.model must not be empty", key)
+	}
+	if llm.Temperature != nil && (*llm.Temperature < 0 || *llm.Temperature > 2) {
+		v.addf("%s.temperature must be between 0 and 2, got %g", key, *llm.Temperature)
+	}
+	if llm.MaxTokens < 0 {
+		v.addf("%s.max_tokens must not be negative, got %d", key, llm.MaxTokens)
+	}
+}
+
+// modelForProvider requires a model in overrides switching away from the base provider,
+// the base model belongs to the base provider
+func (v *validator) modelForProvider(key string, llm types.LLMOverride, baseProvider string) {
+	if llm.Provider != "" && llm.Provider != baseProvider && strings.TrimSpace(llm.Model) == "" {
+		v.addf("%s.model must be set when %s.provider switches to %s", key, key, llm.Provider)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
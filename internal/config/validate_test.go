@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+func TestValidateLLMCommands(t *testing.T) {
+	cfg := Defaults()
+	cfg.LLM.Provider, cfg.LLM.Model = "openai", "gpt-4.1"
+	cfg.LLM.Commands = map[string]types.LLMOverride{
+		"goals align":  {Provider: "deepseek", Model: "deepseek-chat"},
+		"goals review": {Model: "gpt-4.1-mini"},
+		"spot":         {Provider: "ollama"},
+		"align":        {Model: "gpt-4.1-mini"},
+	}
+	cfg.LLM.Templates = map[string]types.LLMOverride{
+		"guide_roadmap.md": {Provider: "openai"},
+	}
+
+	err := Validate(cfg)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("want a ValidationError, got %v", err)
+	}
+
+	want := []string{
+		"llm.commands.spot.model must be set when llm.commands.spot.provider switches to ollama",
+		"llm.commands.align is not a command using the LLM",
+	}
+	if len(validationErr.Problems) != len(want) {
+		t.Fatalf("problems = %q", validationErr.Problems)
+	}
+	for _, problem := range want {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("problems lack %q: %q", problem, validationErr.Problems)
+		}
+	}
+}
+
+func TestValidateInvalidFilterRegex(t *testing.T) {
+	cfg := Defaults()
+	cfg.LLM.Provider, cfg.LLM.Model = "openai", "gpt-4.1"
+	cfg.Filters.ProjectFilterMode = "blacklist"
+	cfg.Filters.ProjectFilterProjects = []string{"work.secret", "/(secret/"}
+
+	err := Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "filters.project_filter_projects[1]: invalid regex /(secret/") {
+		t.Errorf("want invalid regex problem, got %v", err)
+	}
+}
+
+func TestValidateEnumsAreCaseSensitive(t *testing.T) {
+	cfg := Defaults()
+	cfg.LLM.Provider, cfg.LLM.Model = "openai", "gpt-4.1"
+	cfg.Settings.GoalMode = "Tag"
+	cfg.Settings.GoalTag = "goal"
+	cfg.Filters.TagFilterMode = "Blacklist"
+	cfg.Filters.FilterCombine = "OR"
+
+	err := Validate(cfg)
+	for _, key := range []string{"settings.goal_mode", "filters.tag_filter_mode", "filters.filter_combine"} {
+		if err == nil || !strings.Contains(err.Error(), key+" must be one of") {
+			t.Errorf("want problem for %s, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package goals
+
+import (
+	"sort"
+	"time"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// HistoryEntry is a goal that was achieved or abandoned, with the completed tasks linked to it
+type HistoryEntry struct {
+	Goal     types.Task
+	Started  time.Time
+	Ended    time.Time
+	Achieved bool // false for deleted goals
+	Tasks    []types.Task
+}
+
+// Days returns how long the goal was worked on
+func (e HistoryEntry) Days() int {
+	return daysSince(e.Started, e.Ended)
+}
+
+// History returns the completed goals, and the deleted ones if includeDeleted is set, most recent first
+func (m *Manager) History(includeDeleted bool) ([]HistoryEntry, error) {
+	// GetGoals leaves out deleted goals
+	goals, err := m.client.GetTasksWithFilter([]string{m.config.Settings.GoalFilter()})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := m.client.GetTasksWithFilter([]string{"goal.any:", "status:completed"})
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildHistory(goals, tasks, includeDeleted), nil
+}
+
+// BuildHistory returns an entry for every completed goal, and every deleted one if includeDeleted is set,
+// together with the completed tasks and sub-goals linked to it. The most recently ended goal comes first.
+func BuildHistory(goals, tasks []types.Task, includeDeleted bool) []HistoryEntry {
+	var history []HistoryEntry
+	for _, goal := range goals {
+		if goal.Status != "completed" && (goal.Status != "deleted" || !includeDeleted) {
+			continue
+		}
+
+		entry := HistoryEntry{Goal: goal, Started: goal.Entry.Time(), Achieved: goal.Status == "completed"}
+		if goal.End != nil {
+			entry.Ended = goal.End.Time()
+		} else {
+			entry.Ended = goal.Modified.Time()
+		}
+		for _, task := range tasks {
+			if task.LinksGoal(goal.UUID) {
+				entry.Tasks = append(entry.Tasks, task)
+			}
+		}
+		sort.Slice(entry.Tasks, func(i, j int) bool {
+			return entry.Tasks[i].End != nil && (entry.Tasks[j].End == nil || entry.Tasks[i].End.Time().Before(entry.Tasks[j].End.Time()))
+		})
+		history = append(history, entry)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Ended.After(history[j].Ended)
+	})
+	return history
+}
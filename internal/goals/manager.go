@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"strings"
 
 	"github.com/taskvanguard/taskvanguard/internal/taskwarrior"
 	"github.com/taskvanguard/taskvanguard/pkg/types"
@@ -21,16 +22,15 @@ func NewManager(config *types.Config) *Manager {
 	}
 }
 
-// ListGoals returns all goals (tasks in project:goals)
+// ListGoals returns all goals (tasks in project:goals, or tagged or marked by UDA depending on goal_mode)
 func (m *Manager) ListGoals() ([]types.Task, error) {
-	return m.client.GetGoals()
+	return m.client.GetGoals(m.config.Settings)
 }
 
 // AddGoal creates a new goal with the given arguments
 func (m *Manager) AddGoal(args []string) (string, int, error) {
-	// Prepend project:<goal_project_name> to the arguments
-	goalProjectArg := fmt.Sprintf("project:%s", m.config.Settings.GoalProjectName)
-	goalsArgs := append([]string{goalProjectArg}, args...)
+	// Prepend project:<goal_project_name>, +<goal_tag> or <goal_uda>:goal to the arguments
+	goalsArgs := append(m.config.Settings.GoalAddArgs(), args...)
 	return m.client.AddTaskToTaskWarrior(goalsArgs)
 }
 
@@ -39,14 +39,9 @@ func (m *Manager) ModifyGoal(goalID int, args []string) (string, error) {
 	return m.client.ModifyTaskInTaskWarrior(goalID, args)
 }
 
-// DeleteGoal deletes a goal by ID
+// DeleteGoal deletes a goal by ID. Handle its linked tasks first, see CascadeGoal and ReplaceGoalLink.
 func (m *Manager) DeleteGoal(goalID string) error {
-	cmd := exec.Command("task", goalID, "delete")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to delete goal %s: %v\nOutput: %s", goalID, err, string(output))
-	}
-	return nil
+	return m.runTask(goalID, "delete")
 }
 
 // ShowGoal shows details of a goal or task by ID
@@ -54,17 +49,54 @@ func (m *Manager) ShowGoal(id string) (*types.Task, error) {
 	return m.client.GetTaskByID(id)
 }
 
-// LinkTaskToGoal links a task to a goal using the goal UDA
+// LinkTaskToGoal links a task to a goal using the goal UDA, keeping the links to other goals
 func (m *Manager) LinkTaskToGoal(taskID, goalUUID string) error {
-	cmd := exec.Command("task", taskID, "modify", fmt.Sprintf("goal:%s", goalUUID))
+	task, err := m.client.GetTaskByID(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task %s: %v", taskID, err)
+	}
+	if task == nil {
+		return fmt.Errorf("task %s not found", taskID)
+	}
+	if task.LinksGoal(goalUUID) {
+		return nil
+	}
+	return m.setGoals(taskID, append(task.GoalUUIDs(), goalUUID))
+}
+
+// setGoals replaces the goals a task is linked to
+func (m *Manager) setGoals(taskID string, goalUUIDs []string) error {
+	cmd := exec.Command("task", taskID, "modify", "goal:"+strings.Join(goalUUIDs, ","))
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to link task %s to goal %s: %v\nOutput: %s", taskID, goalUUID, err, string(output))
+		return fmt.Errorf("failed to link task %s to goals %s: %v\nOutput: %s", taskID, strings.Join(goalUUIDs, ","), err, string(output))
 	}
 	return nil
 }
 
-// UnlinkTaskFromGoal removes the goal link from a task
+// RemoveGoalLink removes the link to one goal from a task and keeps the others
+func (m *Manager) RemoveGoalLink(taskID, goalUUID string) error {
+	task, err := m.client.GetTaskByID(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task %s: %v", taskID, err)
+	}
+	if task == nil {
+		return fmt.Errorf("task %s not found", taskID)
+	}
+	if !task.LinksGoal(goalUUID) {
+		return errors.New("the task is not linked to this goal")
+	}
+
+	var remaining []string
+	for _, uuid := range task.GoalUUIDs() {
+		if uuid != goalUUID {
+			remaining = append(remaining, uuid)
+		}
+	}
+	return m.setGoals(taskID, remaining)
+}
+
+// UnlinkTaskFromGoal removes all goal links from a task
 func (m *Manager) UnlinkTaskFromGoal(taskID string) error {
 	cmd := exec.Command("task", taskID, "modify", "goal:")
 	output, err := cmd.CombinedOutput()
@@ -74,7 +106,8 @@ func (m *Manager) UnlinkTaskFromGoal(taskID string) error {
 	return nil
 }
 
-// Link determines which ID is a task and which is a goal, then links them
+// Link determines which ID is a task and which is a goal, then links them.
+// If both are goals the first one becomes a sub-goal of the second one.
 func (m *Manager) Link(id1, id2 string) error {
 	task1, err := m.client.GetTaskByID(id1)
 	if err != nil {
@@ -93,14 +126,14 @@ func (m *Manager) Link(id1, id2 string) error {
 	// Determine which is the goal and which is the task
 	var taskID, goalUUID string
 
-	if task1.Project == m.config.Settings.GoalProjectName && task2.Project != m.config.Settings.GoalProjectName {
+	if m.isGoal(task1) && !m.isGoal(task2) {
 		taskID = id2
 		goalUUID = task1.UUID
-	} else if task2.Project == m.config.Settings.GoalProjectName && task1.Project != m.config.Settings.GoalProjectName {
+	} else if m.isGoal(task2) && !m.isGoal(task1) {
 		taskID = id1
 		goalUUID = task2.UUID
-	} else if task1.Project == m.config.Settings.GoalProjectName && task2.Project == m.config.Settings.GoalProjectName {
-		return errors.New("both items are goals - cannot link two goals together")
+	} else if m.isGoal(task1) && m.isGoal(task2) {
+		return m.SetParentGoal(task1, task2)
 	} else {
 		return errors.New("both items are tasks - cannot link two tasks together")
 	}
@@ -124,27 +157,79 @@ func (m *Manager) Unlink(id1, id2 string) error {
 		return errors.New("one or both IDs not found")
 	}
 
-	// Determine which is the task (non-goal)
-	var taskID string
+	// Determine which is the task (non-goal) or sub-goal and which is the goal
+	var taskID, goalUUID string
 
-	if task1.Project == m.config.Settings.GoalProjectName && task2.Project != m.config.Settings.GoalProjectName {
-		taskID = id2
-	} else if task2.Project == m.config.Settings.GoalProjectName && task1.Project != m.config.Settings.GoalProjectName {
-		taskID = id1
+	if m.isGoal(task1) && !m.isGoal(task2) {
+		taskID, goalUUID = id2, task1.UUID
+	} else if m.isGoal(task2) && !m.isGoal(task1) {
+		taskID, goalUUID = id1, task2.UUID
+	} else if m.isGoal(task1) && task1.LinksGoal(task2.UUID) {
+		taskID, goalUUID = id1, task2.UUID // sub-goal of task2
+	} else if m.isGoal(task2) && task2.LinksGoal(task1.UUID) {
+		taskID, goalUUID = id2, task1.UUID
 	} else {
 		return errors.New("cannot determine which item is the task to unlink")
 	}
 
-	return m.UnlinkTaskFromGoal(taskID)
+	return m.RemoveGoalLink(taskID, goalUUID)
+}
+
+// SetParentGoal makes goal a sub-goal of parent, refusing links that would make a goal its own ancestor
+func (m *Manager) SetParentGoal(goal, parent *types.Task) error {
+	if goal.UUID == parent.UUID {
+		return errors.New("a goal cannot be its own parent")
+	}
+
+	goals, err := m.ListGoals()
+	if err != nil {
+		return fmt.Errorf("failed to get goals: %v", err)
+	}
+	byUUID := make(map[string]types.Task, len(goals))
+	for _, g := range goals {
+		byUUID[g.UUID] = g
+	}
+
+	// Walk up from the new parent, reaching goal means goal is already one of its ancestors
+	visited := make(map[string]bool)
+	for current := parent.ParentGoal(); current != "" && !visited[current]; current = byUUID[current].ParentGoal() {
+		if current == goal.UUID {
+			return fmt.Errorf("cannot link: %q is already a parent of %q, this would create a cycle", goal.Description, parent.Description)
+		}
+		visited[current] = true
+	}
+
+	// A goal has a single parent, linking it again moves it
+	return m.setGoals(goal.UUID, []string{parent.UUID})
 }
 
 // GetLinkedTasks returns all tasks linked to a specific goal
 func (m *Manager) GetLinkedTasks(goalUUID string) ([]types.Task, error) {
-	return m.client.GetTasksWithFilter([]string{fmt.Sprintf("goal:%s", goalUUID)})
+	candidates, err := m.client.GetTasksWithFilter([]string{fmt.Sprintf("goal.has:%s", goalUUID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var linked []types.Task
+	for _, task := range candidates {
+		if task.LinksGoal(goalUUID) {
+			linked = append(linked, task)
+		}
+	}
+	return linked, nil
 }
 
-// GetLinkedGoal returns the goal linked to a specific task
+// GetLinkedGoal returns the first goal linked to a specific task
 func (m *Manager) GetLinkedGoal(taskID string) (*types.Task, error) {
+	linked, err := m.GetLinkedGoals(taskID)
+	if err != nil || len(linked) == 0 {
+		return nil, err
+	}
+	return &linked[0], nil
+}
+
+// GetLinkedGoals returns all goals linked to a specific task
+func (m *Manager) GetLinkedGoals(taskID string) ([]types.Task, error) {
 	task, err := m.client.GetTaskByID(taskID)
 	if err != nil {
 		return nil, err
@@ -159,19 +244,26 @@ func (m *Manager) GetLinkedGoal(taskID string) (*types.Task, error) {
 		return nil, nil // No goal linked
 	}
 
-	// Look up the goal by UUID
-	goals, err := m.client.GetGoals()
+	// Look up the goals by UUID
+	goals, err := m.client.GetGoals(m.config.Settings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get goals: %v", err)
 	}
 
+	byUUID := make(map[string]types.Task, len(goals))
 	for _, goal := range goals {
-		if goal.UUID == task.Goal {
-			return &goal, nil
-		}
+		byUUID[goal.UUID] = goal
 	}
 
-	return nil, fmt.Errorf("goal with UUID %s not found", task.Goal)
+	var linked []types.Task
+	for _, uuid := range task.GoalUUIDs() {
+		goal, ok := byUUID[uuid]
+		if !ok {
+			return nil, fmt.Errorf("goal with UUID %s not found, repair the link with 'vanguard goals doctor'", uuid)
+		}
+		linked = append(linked, goal)
+	}
+	return linked, nil
 }
 
 // ShowLinks shows all links for a given ID (goal or task)
@@ -185,19 +277,19 @@ func (m *Manager) ShowLinks(id string) ([]types.Task, error) {
 		return nil, errors.New("task/goal not found")
 	}
 
-	if task.Project == m.config.Settings.GoalProjectName {
+	if m.isGoal(task) {
 		// It's a goal, show linked tasks
 		return m.GetLinkedTasks(task.UUID)
 	} else {
-		// It's a task, show linked goal (return as slice for consistency)
-		linkedGoal, err := m.GetLinkedGoal(id)
+		// It's a task, show linked goals
+		linkedGoals, err := m.GetLinkedGoals(id)
 		if err != nil {
 			return nil, err
 		}
-		if linkedGoal == nil {
+		if linkedGoals == nil {
 			return []types.Task{}, nil
 		}
-		return []types.Task{*linkedGoal}, nil
+		return linkedGoals, nil
 	}
 }
 
@@ -212,13 +304,18 @@ func (m *Manager) ValidateGoalID(id string) error {
 		return errors.New("goal not found")
 	}
 	
-	if task.Project != m.config.Settings.GoalProjectName {
+	if !m.isGoal(task) {
 		return errors.New("ID does not refer to a goal")
 	}
 	
 	return nil
 }
 
+// isGoal reports whether a task is a goal according to goal_mode
+func (m *Manager) isGoal(task *types.Task) bool {
+	return m.config.Settings.IsGoal(*task)
+}
+
 // IsGoal checks if a given ID refers to a goal
 func (m *Manager) IsGoal(id string) (bool, error) {
 	task, err := m.client.GetTaskByID(id)
@@ -230,5 +327,5 @@ func (m *Manager) IsGoal(id string) (bool, error) {
 		return false, nil
 	}
 	
-	return task.Project == m.config.Settings.GoalProjectName, nil
+	return m.isGoal(task), nil
 }
\ No newline at end of file
@@ -0,0 +1,154 @@
+package goals
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// DanglingLink is a task whose goal UDA refers to goals that do not exist (anymore)
+type DanglingLink struct {
+	Task    types.Task
+	Missing []string // UUIDs not belonging to a goal that is pending or completed
+}
+
+// CompleteGoal marks a goal as done
+func (m *Manager) CompleteGoal(goalID string) error {
+	return m.runTask(goalID, "done")
+}
+
+// GetGoal returns the goal with the given ID or UUID
+func (m *Manager) GetGoal(id string) (*types.Task, error) {
+	goal, err := m.client.GetTaskByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if goal == nil {
+		return nil, errors.New("goal not found")
+	}
+	if !m.isGoal(goal) {
+		return nil, errors.New("ID does not refer to a goal")
+	}
+	return goal, nil
+}
+
+// ReplaceGoalLink moves the link of a task from one goal to another. An empty replacement removes the link.
+func (m *Manager) ReplaceGoalLink(task types.Task, goalUUID, replacement string) error {
+	var uuids []string
+	for _, uuid := range task.GoalUUIDs() {
+		if uuid != goalUUID && uuid != replacement {
+			uuids = append(uuids, uuid)
+		}
+	}
+	if replacement != "" {
+		if m.isGoal(&task) {
+			// Sub-goals have a single parent, SetParentGoal refuses cycles
+			parent, err := m.GetGoal(replacement)
+			if err != nil {
+				return err
+			}
+			return m.SetParentGoal(&task, parent)
+		}
+		uuids = append(uuids, replacement)
+	}
+	return m.setGoals(task.UUID, uuids)
+}
+
+// CascadeGoal deletes or completes (action "delete" or "done") the tasks and sub-goals linked to a goal,
+// following sub-goals down to their own tasks. Tasks also linked to another goal are left pending,
+// deleting removes their link while completing keeps it, so they still count towards the achieved goal.
+// Completed tasks are only touched when deleting.
+func (m *Manager) CascadeGoal(goal types.Task, action string) error {
+	linked, err := m.GetLinkedTasks(goal.UUID)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range linked {
+		if task.Status == "deleted" || (action == "done" && task.Status != "pending") {
+			continue
+		}
+		if len(task.GoalUUIDs()) > 1 && !m.isGoal(&task) {
+			if action == "delete" {
+				if err := m.ReplaceGoalLink(task, goal.UUID, ""); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if m.isGoal(&task) {
+			if err := m.CascadeGoal(task, action); err != nil {
+				return err
+			}
+		}
+		if err := m.runTask(task.UUID, action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DanglingLinks returns every task that links to a goal which is deleted, missing or not a goal
+func (m *Manager) DanglingLinks() ([]DanglingLink, error) {
+	goals, err := m.ListGoals()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goals: %v", err)
+	}
+	known := make(map[string]bool, len(goals))
+	for _, goal := range goals {
+		known[goal.UUID] = true
+	}
+
+	tasks, err := m.client.GetTasksWithFilter([]string{"goal.any:", "status.not:deleted"})
+	if err != nil {
+		return nil, err
+	}
+
+	var dangling []DanglingLink
+	for _, task := range tasks {
+		var missing []string
+		for _, uuid := range task.GoalUUIDs() {
+			if !known[uuid] || uuid == task.UUID {
+				missing = append(missing, uuid)
+			}
+		}
+		if len(missing) > 0 {
+			dangling = append(dangling, DanglingLink{Task: task, Missing: missing})
+		}
+	}
+	return dangling, nil
+}
+
+// RepairLink removes the dangling goal references of a task and links it to replacement instead, if given
+func (m *Manager) RepairLink(link DanglingLink, replacement string) error {
+	missing := make(map[string]bool, len(link.Missing))
+	for _, uuid := range link.Missing {
+		missing[uuid] = true
+	}
+
+	task := link.Task
+	var kept []string
+	for _, uuid := range task.GoalUUIDs() {
+		if !missing[uuid] {
+			kept = append(kept, uuid)
+		}
+	}
+	if replacement == "" {
+		return m.setGoals(task.UUID, kept)
+	}
+	task.Goal = strings.Join(kept, ",")
+	return m.ReplaceGoalLink(task, "", replacement)
+}
+
+// runTask runs a TaskWarrior command like done or delete on a single task without asking for confirmation
+func (m *Manager) runTask(id, command string) error {
+	cmd := exec.Command("task", "rc.confirmation=off", id, command)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to run %s on %s: %v\nOutput: %s", command, id, err, string(output))
+	}
+	return nil
+}
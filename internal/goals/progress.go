@@ -0,0 +1,130 @@
+package goals
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// maxBurndownWeeks limits how far back the burndown goes
+const maxBurndownWeeks = 12
+
+// Progress summarises the tasks linked to a goal. Estimates come from the goal_estimate_uda
+// UDA, tasks without an estimate count as 1, so without estimates all numbers are task counts.
+type Progress struct {
+	Completed int
+	Pending   int
+
+	CompletedEstimate float64
+	TotalEstimate     float64
+
+	Velocity  float64    // completed estimate per week over the last goal_velocity_weeks
+	Projected *time.Time // when the remaining estimate is done at the current velocity, nil if unknown or done
+
+	Burndown []BurndownPoint // remaining estimate at the end of each week, oldest first
+}
+
+// BurndownPoint is the remaining estimate of a goal at a point in time
+type BurndownPoint struct {
+	Date      time.Time
+	Remaining float64
+}
+
+// Percent returns the estimate-weighted completion, 0 if there are no linked tasks
+func (p Progress) Percent() int {
+	if p.TotalEstimate == 0 {
+		return 0
+	}
+	return int(p.CompletedEstimate * 100 / p.TotalEstimate)
+}
+
+// Remaining returns the estimate of the pending tasks
+func (p Progress) Remaining() float64 {
+	return p.TotalEstimate - p.CompletedEstimate
+}
+
+// ComputeProgress calculates the progress of a goal from its linked tasks as of now
+func ComputeProgress(tasks []types.Task, settings types.Settings, now time.Time) Progress {
+	var progress Progress
+	weeks := velocityWeeks(settings)
+	velocitySince := now.AddDate(0, 0, -7*weeks)
+
+	for _, task := range tasks {
+		estimate := taskEstimate(task, settings.GoalEstimateUDA)
+		progress.TotalEstimate += estimate
+		if task.Status != "completed" {
+			progress.Pending++
+			continue
+		}
+		progress.Completed++
+		progress.CompletedEstimate += estimate
+		if task.End != nil && task.End.Time().After(velocitySince) {
+			progress.Velocity += estimate
+		}
+	}
+	progress.Velocity /= float64(weeks)
+
+	if remaining := progress.Remaining(); remaining > 0 && progress.Velocity > 0 {
+		projected := now.Add(time.Duration(remaining / progress.Velocity * 7 * 24 * float64(time.Hour)))
+		progress.Projected = &projected
+	}
+
+	progress.Burndown = burndown(tasks, settings.GoalEstimateUDA, now)
+	return progress
+}
+
+// burndown returns the remaining estimate at the end of every week since the first linked task was created
+func burndown(tasks []types.Task, estimateUDA string, now time.Time) []BurndownPoint {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	start := now
+	for _, task := range tasks {
+		if entry := task.Entry.Time(); !entry.IsZero() && entry.Before(start) {
+			start = entry
+		}
+	}
+	if earliest := now.AddDate(0, 0, -7*(maxBurndownWeeks-1)); start.Before(earliest) {
+		start = earliest
+	}
+
+	var points []BurndownPoint
+	for date := start.AddDate(0, 0, 7); ; date = date.AddDate(0, 0, 7) {
+		if date.After(now) {
+			date = now
+		}
+		point := BurndownPoint{Date: date}
+		for _, task := range tasks {
+			if task.Entry.Time().After(date) {
+				continue
+			}
+			if task.Status == "completed" && task.End != nil && !task.End.Time().After(date) {
+				continue
+			}
+			point.Remaining += taskEstimate(task, estimateUDA)
+		}
+		points = append(points, point)
+		if date.Equal(now) {
+			return points
+		}
+	}
+}
+
+// velocityWeeks returns goal_velocity_weeks, 4 if it is not set
+func velocityWeeks(settings types.Settings) int {
+	if settings.GoalVelocityWeeks <= 0 {
+		return 4
+	}
+	return settings.GoalVelocityWeeks
+}
+
+// taskEstimate reads the estimate UDA of a task, 1 if it is not set
+func taskEstimate(task types.Task, estimateUDA string) float64 {
+	var estimate float64
+	if raw, ok := task.UDAs[estimateUDA]; ok && json.Unmarshal(raw, &estimate) == nil && estimate > 0 {
+		return estimate
+	}
+	return 1
+}
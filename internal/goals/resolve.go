@@ -0,0 +1,95 @@
+package goals
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// idPattern matches what TaskWarrior takes as a task reference: a numeric ID, a UUID or a short UUID
+var idPattern = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})?)$`)
+
+// IsTaskRef reports whether arg refers to a task by ID or UUID rather than being a goal name
+func IsTaskRef(arg string) bool {
+	return idPattern.MatchString(arg)
+}
+
+// FindGoals returns the goals whose description matches query, see MatchGoals
+func (m *Manager) FindGoals(query string) ([]types.Task, error) {
+	goals, err := m.ListGoals()
+	if err != nil {
+		return nil, err
+	}
+	return MatchGoals(goals, query), nil
+}
+
+// NamedGoals returns the goals whose description is name, ignoring case. Pending goals come first.
+func NamedGoals(goals []types.Task, name string) []types.Task {
+	matches := MatchGoals(goals, name)
+	if len(matches) == 0 || !strings.EqualFold(matches[0].Description, strings.TrimSpace(name)) {
+		return nil
+	}
+	return matches
+}
+
+// MatchGoals returns the goals matching query, ignoring case. Only the best kind of match is returned:
+// goals named exactly like query, else goals containing every word of query, else goals containing
+// the letters of query in order ("mrthn" finds "Run a marathon"). Pending goals come first.
+func MatchGoals(goals []types.Task, query string) []types.Task {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	best := 0
+	var matches []types.Task
+	for _, goal := range goals {
+		score := matchScore(strings.ToLower(goal.Description), query)
+		if score == 0 || score < best {
+			continue
+		}
+		if score > best {
+			best, matches = score, nil
+		}
+		matches = append(matches, goal)
+	}
+
+	var pending, other []types.Task
+	for _, goal := range matches {
+		if goal.Status == "pending" {
+			pending = append(pending, goal)
+		} else {
+			other = append(other, goal)
+		}
+	}
+	return append(pending, other...)
+}
+
+func matchScore(description, query string) int {
+	if description == query {
+		return 3
+	}
+
+	allWords := true
+	for _, word := range strings.Fields(query) {
+		if !strings.Contains(description, word) {
+			allWords = false
+			break
+		}
+	}
+	if allWords {
+		return 2
+	}
+
+	rest := []rune(query)
+	for _, r := range description {
+		if len(rest) > 0 && r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	if len(rest) == 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,37 @@
+package goals
+
+import (
+	"testing"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+func TestNamedGoals(t *testing.T) {
+	goals := []types.Task{
+		{UUID: "a", Description: "Save, then invest", Status: "pending"},
+		{UUID: "b", Description: "2027", Status: "pending"},
+		{UUID: "c", Description: "Save money", Status: "pending"},
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"save, then invest", "a"},
+		{" 2027 ", "b"},
+		{"save", ""},
+		{"12", ""},
+	}
+	for _, tt := range tests {
+		named := NamedGoals(goals, tt.name)
+		got := ""
+		if len(named) == 1 {
+			got = named[0].UUID
+		} else if len(named) > 1 {
+			got = "several"
+		}
+		if got != tt.want {
+			t.Errorf("NamedGoals(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
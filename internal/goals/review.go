@@ -0,0 +1,97 @@
+package goals
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// ReviewItem is a goal that needs attention in a review together with the reasons why
+type ReviewItem struct {
+	Node    *GoalNode
+	Reasons []string
+}
+
+// ReviewItems returns the pending goals of a tree, sub-goals included, that are overdue,
+// due for review, stale (no linked task completed in goal_stale_days) or have no pending tasks
+func ReviewItems(nodes []*GoalNode, settings types.Settings, now time.Time) []ReviewItem {
+	var items []ReviewItem
+	for _, node := range nodes {
+		if reasons := reviewReasons(node, settings, now); len(reasons) > 0 {
+			items = append(items, ReviewItem{Node: node, Reasons: reasons})
+		}
+		items = append(items, ReviewItems(node.Children, settings, now)...)
+	}
+	return items
+}
+
+func reviewReasons(node *GoalNode, settings types.Settings, now time.Time) []string {
+	goal := node.Goal
+	if goal.Status != "pending" {
+		return nil
+	}
+
+	var reasons []string
+	if goal.Due != nil && goal.Due.Time().Before(now) {
+		reasons = append(reasons, fmt.Sprintf("overdue since %s", goal.Due.Time().Local().Format("2006-01-02")))
+	}
+
+	lastReview := goal.Entry.Time()
+	if goal.Reviewed != nil {
+		lastReview = goal.Reviewed.Time()
+	}
+	if interval := ReviewInterval(goal, settings); now.Sub(lastReview) > interval {
+		reasons = append(reasons, fmt.Sprintf("not reviewed for %d days", daysSince(lastReview, now)))
+	}
+
+	if node.Progress.Pending == 0 {
+		reasons = append(reasons, "no pending tasks")
+	}
+
+	staleAfter := time.Duration(settings.GoalStaleDays) * 24 * time.Hour
+	if lastDone := lastCompletion(node.Tasks); lastDone.IsZero() {
+		if len(node.Tasks) > 0 && now.Sub(goal.Entry.Time()) > staleAfter {
+			reasons = append(reasons, "no linked task completed yet")
+		}
+	} else if now.Sub(lastDone) > staleAfter {
+		reasons = append(reasons, fmt.Sprintf("no linked task completed in %d days", daysSince(lastDone, now)))
+	}
+
+	return reasons
+}
+
+// ReviewInterval returns how often a goal should be reviewed, its review UDA or goal_review_days
+func ReviewInterval(goal types.Task, settings types.Settings) time.Duration {
+	days := float64(settings.GoalReviewDays)
+	if goal.Review > 0 {
+		days = goal.Review
+	}
+	return time.Duration(days * 24 * float64(time.Hour))
+}
+
+// lastCompletion returns when the most recently completed task was done, zero if none is
+func lastCompletion(tasks []types.Task) time.Time {
+	var last time.Time
+	for _, task := range tasks {
+		if task.Status == "completed" && task.End != nil && task.End.Time().After(last) {
+			last = task.End.Time()
+		}
+	}
+	return last
+}
+
+func daysSince(t, now time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
+
+// MarkReviewed sets the reviewed UDA of a goal to now
+func (m *Manager) MarkReviewed(goalUUID string) error {
+	cmd := exec.Command("task", goalUUID, "modify", "reviewed:now")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to mark goal %s as reviewed: %v\nOutput: %s", goalUUID, err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package goals
+
+import (
+	"errors"
+	"time"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// GoalNode is a goal in the goal hierarchy. Sub-goals link to their parent through the goal UDA like tasks do.
+type GoalNode struct {
+	Goal     types.Task
+	Children []*GoalNode
+
+	// Linked tasks of the goal and all its sub-goals, goals themselves are not included
+	Tasks    []types.Task
+	Progress Progress
+}
+
+// GoalTree returns the top-level goals with their sub-goals and the progress of linked tasks rolled up to parents
+func (m *Manager) GoalTree() ([]*GoalNode, error) {
+	goals, err := m.ListGoals()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := m.client.GetTasksWithFilter([]string{"goal.any:", "status.not:deleted"})
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildGoalTree(goals, tasks, m.config.Settings, time.Now()), nil
+}
+
+// GoalProgress returns the goal with the given ID together with its sub-goals and progress
+func (m *Manager) GoalProgress(id string) (*GoalNode, error) {
+	goal, err := m.client.GetTaskByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if goal == nil {
+		return nil, errors.New("goal not found")
+	}
+	if !m.isGoal(goal) {
+		return nil, errors.New("ID does not refer to a goal")
+	}
+
+	tree, err := m.GoalTree()
+	if err != nil {
+		return nil, err
+	}
+	node := FindGoalNode(tree, goal.UUID)
+	if node == nil {
+		return nil, errors.New("goal not found")
+	}
+	return node, nil
+}
+
+// Settings returns the settings goals are managed with
+func (m *Manager) Settings() types.Settings {
+	return m.config.Settings
+}
+
+// VelocityWeeks returns the number of past weeks velocity is averaged over
+func (m *Manager) VelocityWeeks() int {
+	return velocityWeeks(m.config.Settings)
+}
+
+// FindGoalNode returns the node of the goal with the given UUID, nil if it is not in the tree
+func FindGoalNode(nodes []*GoalNode, uuid string) *GoalNode {
+	for _, node := range nodes {
+		if node.Goal.UUID == uuid {
+			return node
+		}
+		if found := FindGoalNode(node.Children, uuid); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// BuildGoalTree arranges goals by their parent links. Goals whose parent is unknown or
+// that are part of a cycle are shown at the top level instead of being dropped.
+func BuildGoalTree(goals []types.Task, tasks []types.Task, settings types.Settings, now time.Time) []*GoalNode {
+	nodes := make(map[string]*GoalNode, len(goals))
+	for _, goal := range goals {
+		nodes[goal.UUID] = &GoalNode{Goal: goal}
+	}
+
+	var roots []*GoalNode
+	for _, goal := range goals {
+		node := nodes[goal.UUID]
+		parent, ok := nodes[goal.ParentGoal()]
+		if !ok || inCycle(nodes, goal.UUID) {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, task := range tasks {
+		if settings.IsGoal(task) {
+			continue
+		}
+		for _, uuid := range task.GoalUUIDs() {
+			if node, ok := nodes[uuid]; ok {
+				node.Tasks = append(node.Tasks, task)
+			}
+		}
+	}
+
+	for _, root := range roots {
+		root.rollUp(settings, now)
+	}
+	return roots
+}
+
+// rollUp adds the tasks of all sub-goals to the node and computes its progress.
+// Tasks linked to several goals of the same branch are counted once.
+func (n *GoalNode) rollUp(settings types.Settings, now time.Time) {
+	seen := make(map[string]bool, len(n.Tasks))
+	for _, task := range n.Tasks {
+		seen[task.UUID] = true
+	}
+	for _, child := range n.Children {
+		child.rollUp(settings, now)
+		for _, task := range child.Tasks {
+			if !seen[task.UUID] {
+				seen[task.UUID] = true
+				n.Tasks = append(n.Tasks, task)
+			}
+		}
+	}
+	n.Progress = ComputeProgress(n.Tasks, settings, now)
+}
+
+// inCycle reports whether following the parent links from a goal leads back to it
+func inCycle(nodes map[string]*GoalNode, uuid string) bool {
+	visited := make(map[string]bool)
+	for current := nodes[uuid].Goal.ParentGoal(); current != ""; {
+		if current == uuid {
+			return true
+		}
+		node, ok := nodes[current]
+		if !ok || visited[current] {
+			return false
+		}
+		visited[current] = true
+		current = node.Goal.ParentGoal()
+	}
+	return false
+}
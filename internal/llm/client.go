@@ -7,11 +7,14 @@ import (
 
 	"github.com/taskvanguard/taskvanguard/pkg/types"
 	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/ollama"
 	"github.com/tmc/langchaingo/llms/openai"
 )
 
 type Client struct {
-	llm llms.Model
+	llm         llms.Model
+	temperature *float64
+	maxTokens   int
 }
 
 type Message struct {
@@ -47,6 +50,14 @@ func NewClient(cfg *types.LLMConfig) (*Client, error) {
 			opts = append(opts, openai.WithBaseURL(cfg.BaseURL))
 		}
 		model, err = openai.New(opts...)
+	case "ollama":
+		opts := []ollama.Option{
+			ollama.WithModel(cfg.Model),
+		}
+		if cfg.BaseURL != "" {
+			opts = append(opts, ollama.WithServerURL(cfg.BaseURL))
+		}
+		model, err = ollama.New(opts...)
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", cfg.Provider)
 	}
@@ -56,7 +67,9 @@ func NewClient(cfg *types.LLMConfig) (*Client, error) {
 	}
 
 	return &Client{
-		llm: model,
+		llm:         model,
+		temperature: cfg.Temperature,
+		maxTokens:   cfg.MaxTokens,
 	}, nil
 }
 
@@ -75,8 +88,16 @@ func (c *Client) Chat(messages []Message) (string, error) {
 		}
 	}
 
+	var opts []llms.CallOption
+	if c.temperature != nil {
+		opts = append(opts, llms.WithTemperature(*c.temperature))
+	}
+	if c.maxTokens > 0 {
+		opts = append(opts, llms.WithMaxTokens(c.maxTokens))
+	}
+
 	ctx := context.Background()
-	completion, err := c.llm.GenerateContent(ctx, llmMessages)
+	completion, err := c.llm.GenerateContent(ctx, llmMessages, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,186 @@
+package privacy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/internal/config"
+	"github.com/taskvanguard/taskvanguard/internal/llm"
+	"github.com/taskvanguard/taskvanguard/pkg/filter"
+	"github.com/taskvanguard/taskvanguard/pkg/redact"
+	"github.com/taskvanguard/taskvanguard/pkg/theme"
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// skipPrefix marks annotations written by spot when a task is skipped, the only annotations sent to the LLM
+const skipPrefix = "Skipped:"
+
+// Gate is the single path for everything sent to the LLM. It drops tasks excluded by the filters,
+// reduces tasks to the fields prompts need and redacts sensitive text. One Gate should be used per command.
+type Gate struct {
+	cfg      *types.Config
+	redactor *redact.Redactor
+	clients  map[string]*llm.Client // per template, they can use different models
+}
+
+// TaskView is the part of a task that may be sent to the LLM
+type TaskView struct {
+	ID              int      `json:"id"`
+	Description     string   `json:"description"`
+	Project         string   `json:"project,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Priority        string   `json:"priority,omitempty"`
+	Due             string   `json:"due,omitempty"`
+	Urgency         float64  `json:"urgency,omitempty"`
+	Skipped         float64  `json:"skipped,omitempty"`
+	History         []string `json:"history,omitempty"`
+	GoalDescription string   `json:"goal_description,omitempty"`
+}
+
+// Blocked is a task the gate refused to send together with the reason
+type Blocked struct {
+	Task   types.Task
+	Reason string
+}
+
+// NewGate creates a gate from config
+func NewGate(cfg *types.Config) (*Gate, error) {
+	redactor, err := redact.New(cfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+	return &Gate{cfg: cfg, redactor: redactor, clients: make(map[string]*llm.Client)}, nil
+}
+
+// Check returns an error if a task with this project and these tags must not be sent to the LLM
+func (g *Gate) Check(project string, tags []string) error {
+	if filter.ShouldInclude(project, tags, g.cfg.Filters) {
+		return nil
+	}
+	if !filter.ShouldIncludeByTags(tags, g.cfg.Filters) {
+		return fmt.Errorf("at least one of the tags is blacklisted for LLM processing: tags=%v", tags)
+	}
+	return fmt.Errorf("the project this task is assigned to is blacklisted for LLM processing: project=%q", project)
+}
+
+// Partition splits tasks into the ones that may be sent to the LLM and the ones that are blocked
+func (g *Gate) Partition(tasks []types.Task) (allowed []types.Task, blocked []Blocked) {
+	for _, task := range tasks {
+		if err := g.Check(task.Project, task.Tags); err != nil {
+			blocked = append(blocked, Blocked{Task: task, Reason: err.Error()})
+			continue
+		}
+		allowed = append(allowed, task)
+	}
+	return allowed, blocked
+}
+
+// Goals returns the goals that may be sent, with redacted descriptions
+func (g *Gate) Goals(goals []types.Task) []types.Task {
+	allowed, _ := g.Partition(goals)
+	redacted := make([]types.Task, 0, len(allowed))
+	for _, goal := range allowed {
+		redacted = append(redacted, g.redactor.RedactTask(goal))
+	}
+	return redacted
+}
+
+// Projects returns the project names that may be sent
+func (g *Gate) Projects(projects []string) []string {
+	return filter.FilterProjects(projects, g.cfg)
+}
+
+// AllowTag reports whether a tag name may be sent
+func (g *Gate) AllowTag(tag string) bool {
+	return filter.ShouldIncludeByTag(tag, g.cfg.Filters)
+}
+
+// View reduces a task to the fields in TaskView and redacts its text
+func (g *Gate) View(task types.Task, goalDescription string) TaskView {
+	view := TaskView{
+		ID:              task.ID,
+		Description:     g.Text(task.Description),
+		Project:         task.Project,
+		Tags:            task.Tags,
+		Priority:        task.Priority,
+		Urgency:         task.Urgency,
+		Skipped:         task.Skipped,
+		GoalDescription: g.Text(goalDescription),
+	}
+	if task.Due != nil {
+		view.Due = task.Due.Time().Format("2006-01-02")
+	}
+	for _, annotation := range task.Annotations {
+		if strings.HasPrefix(annotation.Description, skipPrefix) {
+			view.History = append(view.History, g.Text(annotation.Description))
+		}
+	}
+	return view
+}
+
+// Text redacts free text such as task titles or answers
+func (g *Gate) Text(text string) string {
+	return g.redactor.Redact(text)
+}
+
+// Restore maps placeholders in text shown to the user back to the original values
+func (g *Gate) Restore(text string) string {
+	return g.redactor.Restore(text)
+}
+
+// Send sends a prompt rendered from template to the LLM and returns the response with redacted values restored.
+// template selects per-template LLM settings and may be empty for prompts built in code.
+func (g *Gate) Send(template string, prompt string) (string, error) {
+	if !g.cfg.Settings.EnableLLM {
+		return "", fmt.Errorf("sending API Request to LLM is disabled via config")
+	}
+
+	llmConfig := g.cfg.LLM.ForTemplate(template)
+	if err := llmConfig.CheckModel(); err != nil {
+		return "", fmt.Errorf("%s (llm.templates.%s)", err, template)
+	}
+	client, ok := g.clients[template]
+	if !ok {
+		// Templates switching to another provider need that provider's key
+		apiKey, err := config.ResolveAPIKey(llmConfig)
+		if err != nil {
+			return "", err
+		}
+		// A local ollama model does not need a key
+		if apiKey == "" && llmConfig.Provider != "ollama" {
+			return "", config.MissingKeyError(llmConfig.Provider)
+		}
+		llmConfig.APIKey = apiKey
+
+		client, err = llm.NewClient(&llmConfig)
+		if err != nil {
+			return "", fmt.Errorf("init llm client: %w", err)
+		}
+		g.clients[template] = client
+	}
+
+	label := llmConfig.Provider + "/" + llmConfig.Model
+	if template != "" {
+		label = template + ", " + label
+	}
+
+	messages := []llm.Message{
+		{Role: "user", Content: prompt},
+	}
+
+	if g.cfg.Settings.Debug {
+		fmt.Println(theme.Title("LLM Request (" + label + ")"))
+		fmt.Println(theme.Info(messages))
+	}
+
+	response, err := client.Chat(messages)
+	if g.cfg.Settings.Debug {
+		fmt.Println(theme.Title("LLM Response (" + label + ")"))
+		fmt.Println(theme.Info(response))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return g.redactor.RestoreJSON(response), nil
+}
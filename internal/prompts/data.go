@@ -0,0 +1,60 @@
+package prompts
+
+import "github.com/taskvanguard/taskvanguard/internal/privacy"
+
+// Data passed to the prompt templates besides TemplateData, one struct per template.
+// All text in them must already have passed the privacy gate.
+
+// SpotlightData fills spotlight.md, RecentTasks are recently completed and DeferredTasks skipped task descriptions
+type SpotlightData struct {
+	Mood          string
+	Location      string
+	Time          string
+	RecentTasks   []string
+	DeferredTasks []string
+	Tasks         []privacy.TaskView
+	AchievedGoals []AchievedGoal
+}
+
+// QuestionAnswer is one exchange of a guide session
+type QuestionAnswer struct {
+	Question string
+	Answer   string
+}
+
+// GuideQuestionData fills guide_questions.md
+type GuideQuestionData struct {
+	QAHistory         []QuestionAnswer
+	QuestionThreshold int
+	QuestionCount     int
+}
+
+// GuideSummaryData fills guide_summary.md
+type GuideSummaryData struct {
+	QAHistory []QuestionAnswer
+}
+
+// GuideRoadmapData fills guide_roadmap.md, UserTags may be empty
+type GuideRoadmapData struct {
+	GoalSummary    string
+	AnswersSummary string
+	UserTags       []string
+	AchievedGoals  []AchievedGoal
+}
+
+// NextActionsData fills goal_next_actions.md, GoalDue is empty if the goal has no due date
+type NextActionsData struct {
+	GoalDescription string
+	GoalDue         string
+	Reasons         []string
+	PendingTasks    []string
+	CompletedTasks  []string
+	UserTags        []string
+	AchievedGoals   []AchievedGoal
+}
+
+// AlignmentData fills goal_alignment.md
+type AlignmentData struct {
+	Goals []Goal
+	Tasks []privacy.TaskView
+}
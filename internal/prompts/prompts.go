@@ -2,10 +2,15 @@ package prompts
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 
 	"text/template"
 
 	"github.com/taskvanguard/taskvanguard/assets"
+	"github.com/taskvanguard/taskvanguard/internal/config"
 	"github.com/taskvanguard/taskvanguard/pkg/types"
 )
 
@@ -25,15 +30,24 @@ type Tag struct {
 }
 
 type Goal struct {
+	ID          int
 	Description string
 	Priority 	string
 }
 
+// AchievedGoal is a completed goal, condensed for settings.goal_history_in_prompts
+type AchievedGoal struct {
+	Description string
+	Completed   string // date
+	Days        int    // from creation to completion
+}
+
 type UserContext struct {
 	UserTags        	[]Tag
 	UserAnnotations 	[]Annotation
 	UserProjects    	[]string
 	UserGoals           []Goal
+	AchievedGoals       []AchievedGoal
 }
 
 type Annotation struct {
@@ -48,22 +62,35 @@ type TemplateData struct {
 	ExampleOutput 	string 
 }
 
-// RenderTemplate renders a Markdown template from path with the given data
-func RenderTemplate(filename string, data TemplateData) (string, error) {
+// funcMap holds the functions available in templates
+var funcMap = template.FuncMap{
+	"add":  func(a, b int) int { return a + b },
+	"join": strings.Join,
+	"json": func(v any) (string, error) {
+		b, err := json.MarshalIndent(v, "", "  ")
+		return string(b), err
+	},
+}
+
+// Partials can be included by every template with {{ template "persona.md" . }}
+var Partials = []string{"persona.md", "policy.md", "task_policy.md", "user.md", "user_context.md", "achieved_goals.md"}
+
+// RenderTemplate renders a Markdown template from path with the given data,
+// which is TemplateData for the task analysis templates and the matching *Data struct for the others
+func RenderTemplate(filename string, data any) (string, error) {
 	tmplBytes, err := LoadPrompt(filename)
 	if err != nil {
 		return "", err
 	}
 
-	funcMap := template.FuncMap{
-		"add": func(a, b int) int { return a + b },
-	}
-
 	tmpl := template.New("prompt").Funcs(funcMap)
 
-	userContextBytes, err := LoadPrompt("user_context.md")
-	if err == nil {
-		_, err = tmpl.New("user_context.md").Parse(string(userContextBytes))
+	for _, partial := range Partials {
+		partialBytes, err := LoadPrompt(partial)
+		if err != nil {
+			return "", err
+		}
+		_, err = tmpl.New(partial).Parse(string(partialBytes))
 		if err != nil {
 			return "", err
 		}
@@ -83,7 +110,20 @@ func RenderTemplate(filename string, data TemplateData) (string, error) {
 	return buf.String(), nil
 }
 
+// LoadPrompt returns the user's override of a template from the templates directory if there is one, else the embedded template.
+// Overrides are parsed first, so a broken override fails with its path instead of producing a garbled prompt.
 func LoadPrompt(filename string) (string, error) {
+	override, path, ok, err := config.ReadUserTemplate(filename)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		if err := ValidateTemplate(filename, override); err != nil {
+			return "", fmt.Errorf("template override %s: %v (see 'vanguard templates diff %s' or 'vanguard templates reset %s')", path, err, filename, filename)
+		}
+		return override, nil
+	}
+
 	prompt, err := assets.Load(filename)
 	if err != nil {
 		return "", err
@@ -91,15 +131,48 @@ func LoadPrompt(filename string) (string, error) {
 	return prompt, nil
 }
 
+// ValidateTemplate parses a template the way RenderTemplate does
+func ValidateTemplate(filename string, content string) error {
+	_, err := template.New(filename).Funcs(funcMap).Parse(content)
+	return err
+}
+
 func ToPromptGoals(tasks []types.Task) []Goal {
 
 	goals := make([]Goal, 0, len(tasks))
 
 	for _, t := range tasks {
 		goals = append(goals, Goal{
+			ID:          t.ID,
 			Description: t.Description,
 			Priority:    t.Priority,
 		})
 	}
 	return goals
-}
\ No newline at end of file
+}
+
+// ToAchievedGoals returns the limit most recently completed goals, other goals are skipped
+func ToAchievedGoals(tasks []types.Task, limit int) []AchievedGoal {
+	var completed []types.Task
+	for _, t := range tasks {
+		if t.Status == "completed" && t.End != nil {
+			completed = append(completed, t)
+		}
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].End.Time().After(completed[j].End.Time())
+	})
+	if len(completed) > limit {
+		completed = completed[:limit]
+	}
+
+	achieved := make([]AchievedGoal, 0, len(completed))
+	for _, t := range completed {
+		achieved = append(achieved, AchievedGoal{
+			Description: t.Description,
+			Completed:   t.End.Time().Local().Format("2006-01-02"),
+			Days:        int(t.End.Time().Sub(t.Entry.Time()).Hours() / 24),
+		})
+	}
+	return achieved
+}
@@ -0,0 +1,164 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/taskvanguard/taskvanguard/internal/config"
+	"github.com/taskvanguard/taskvanguard/internal/privacy"
+)
+
+// useTemplatesDir points template overrides to an empty temp dir, so the user's own overrides don't leak into tests
+func useTemplatesDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("TASKVANGUARD_TEMPLATES", dir)
+	return dir
+}
+
+func analysisData() TemplateData {
+	task := Task{Description: "renew passport", Tags: []string{"admin"}, Project: "pers.travel"}
+	return TemplateData{
+		Task:  task,
+		Tasks: []Task{task, {Description: "book flights"}},
+		UserContext: UserContext{
+			UserTags:        []Tag{{Name: "fast", Description: "Quick win"}},
+			UserAnnotations: []Annotation{{Name: "tip", Description: "One execution tip"}},
+			UserProjects:    []string{"pers.travel"},
+			UserGoals:       []Goal{{ID: 3, Description: "Visit Japan", Priority: "H"}},
+			AchievedGoals:   []AchievedGoal{{Description: "Learn kana", Completed: "2026-01-10", Days: 40}},
+		},
+		ExampleOutput: `{"tip": "One execution tip"}`,
+	}
+}
+
+var views = []privacy.TaskView{
+	{ID: 7, Description: "renew passport", Project: "pers.travel", Tags: []string{"admin", "fast"}, Urgency: 9.25, Skipped: 2,
+		History: []string{"Skipped: no photo"}, GoalDescription: "Visit Japan", Due: "2026-11-01"},
+	{ID: 8, Description: "book flights"},
+}
+
+func TestRenderTemplates(t *testing.T) {
+	useTemplatesDir(t)
+
+	tests := []struct {
+		name string
+		data any
+		want []string
+	}{
+		{"task_analysis_single.md", analysisData(), []string{
+			"# Your Persona", "Task Refinement Directive", "- Description: renew passport", "Visit Japan (Priority: H)",
+			"fast: Quick win", "- tip: One execution tip", `"additional_infos": {"tip": "One execution tip"}`, "Learn kana (achieved 2026-01-10 after 40 days)",
+		}},
+		{"task_analysis_extended.md", analysisData(), []string{
+			"Task Refinement Directive", "- Description: renew passport", "Visit Japan (Priority: H)", `"additional_infos": {"tip": "One execution tip"}`, "get a new job in secops",
+		}},
+		{"task_analysis_batch.md", analysisData(), []string{
+			"### Task 1\n- Description: renew passport", "### Task 2\n- Description: book flights",
+		}},
+		{"spotlight.md", SpotlightData{
+			Mood: "tired", Location: "home", Time: "9:00 PM",
+			RecentTasks: []string{"pay rent", "call mum"}, DeferredTasks: []string{"renew passport"},
+			Tasks: views, AchievedGoals: []AchievedGoal{{Description: "Learn kana", Completed: "2026-01-10", Days: 40}},
+		}, []string{
+			"# Your Persona", "Current mood: tired", "Location/context tag: home", "Time of day: 9:00 PM",
+			"Recent completions: pay rent; call mum", "Recently skipped tasks: renew passport",
+			`- ID 7: "renew passport" [urgency: 9.2, tags: admin, fast, project: pers.travel], skipped: 2, Due: 2026-11-01`,
+			"Goal: Visit Japan", "History: Skipped: no photo", `- ID 8: "book flights" [urgency: 0.0], skipped: 0, Due: (none)`, "Learn kana", `"context_tag"`, `"next"`,
+		}},
+		{"guide_questions.md", GuideQuestionData{
+			QAHistory:         []QuestionAnswer{{Question: "What is the goal?", Answer: "Run a marathon"}},
+			QuestionThreshold: 6,
+			QuestionCount:     1,
+		}, []string{"Q1: What is the goal?\nA1: Run a marathon", "1/6 questions"}},
+		{"guide_summary.md", GuideSummaryData{
+			QAHistory: []QuestionAnswer{{Question: "When?", Answer: "In May"}, {Question: "Budget?", Answer: "None"}},
+		}, []string{"Q1: When?\nA1: In May", "Q2: Budget?\nA2: None"}},
+		{"guide_roadmap.md", GuideRoadmapData{
+			GoalSummary: "Run a marathon in May", AnswersSummary: "- Runs 10k now", UserTags: []string{"key", "fast"},
+		}, []string{"Run a marathon in May", "- Runs 10k now", "relevant tags from key, fast."}},
+		{"guide_roadmap.md", GuideRoadmapData{GoalSummary: "Run a marathon in May"}, []string{"key, sb, fast, cut, ai"}},
+		{"goal_next_actions.md", NextActionsData{
+			GoalDescription: "Visit Japan", Reasons: []string{"overdue"},
+			PendingTasks: []string{"renew passport"}, UserTags: []string{"key"},
+		}, []string{"Visit Japan", "Due: none", "- overdue", "- renew passport", "Recently completed tasks:\nnone", "relevant tags from key."}},
+		{"goal_alignment.md", AlignmentData{
+			Goals: []Goal{{ID: 3, Description: "Visit Japan"}},
+			Tasks: views,
+		}, []string{"- [3] Visit Japan", "- [7] renew passport project:pers.travel +admin +fast", "- [8] book flights\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate(tt.name, tt.data)
+			if err != nil {
+				t.Fatalf("RenderTemplate: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output lacks %q:\n%s", want, got)
+				}
+			}
+			if strings.Contains(got, "<no value>") {
+				t.Errorf("output has unset fields:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestRenderTemplatesCoversAll(t *testing.T) {
+	names, err := config.DefaultTemplateNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tested := []string{"task_analysis_single.md", "task_analysis_extended.md", "task_analysis_batch.md", "spotlight.md",
+		"guide_questions.md", "guide_summary.md", "guide_roadmap.md", "goal_next_actions.md", "goal_alignment.md"}
+	for _, name := range names {
+		if !slices.Contains(Partials, name) && !slices.Contains(tested, name) {
+			t.Errorf("%s is neither a partial nor rendered in TestRenderTemplates", name)
+		}
+	}
+}
+
+func TestTaskPolicyOnlyInAnalysis(t *testing.T) {
+	useTemplatesDir(t)
+
+	got, err := RenderTemplate("spotlight.md", SpotlightData{Tasks: views})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "Task Refinement Directive") {
+		t.Errorf("spotlight prompt includes the task refinement directive")
+	}
+}
+
+func TestRenderTemplateOverride(t *testing.T) {
+	dir := useTemplatesDir(t)
+	if err := os.WriteFile(filepath.Join(dir, "persona.md"), []byte("You are a pirate."), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := RenderTemplate("goal_alignment.md", AlignmentData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "You are a pirate.") || strings.Contains(got, "# Your Persona") {
+		t.Errorf("persona override not applied:\n%s", got)
+	}
+}
+
+func TestRenderTemplateBrokenOverride(t *testing.T) {
+	dir := useTemplatesDir(t)
+	path := filepath.Join(dir, "spotlight.md")
+	if err := os.WriteFile(path, []byte("{{ .Mood "), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := RenderTemplate("spotlight.md", SpotlightData{})
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("want error naming %s, got %v", path, err)
+	}
+}
@@ -28,7 +28,7 @@ func NewStateManager(config *types.Config) (*StateManager, error) {
 	
 	statePath := filepath.Join(configDir, "taskvanguard", "state.json")
 	
-	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(statePath), 0700); err != nil {
 		return nil, err
 	}
 	
@@ -49,7 +49,7 @@ func (sm *StateManager) SaveContext(context TaskContext) error {
 		return err
 	}
 	
-	return os.WriteFile(sm.statePath, data, 0644)
+	return os.WriteFile(sm.statePath, data, 0600)
 }
 
 func (sm *StateManager) LoadContext() (TaskContext, error) {
@@ -0,0 +1,203 @@
+package taskwarrior
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// TaskChange is a set of field changes for a single task. Empty fields are left untouched,
+// ClearProject and ClearPriority remove the value.
+type TaskChange struct {
+	UUID string
+	// Modified is the modification time of the task when it was fetched, used to detect concurrent edits
+	Modified      types.TWTime
+	Description   string
+	Project       string
+	Priority      string
+	ClearProject  bool
+	ClearPriority bool
+	AddTags       []string
+	RemoveTags    []string
+}
+
+// Conflict is a change that was not applied because the task was modified since it was fetched
+type Conflict struct {
+	Change  TaskChange
+	Current types.Task
+}
+
+// ChangeFromSuggestion builds a TaskChange for task from an accepted LLM suggestion
+func ChangeFromSuggestion(task types.Task, s types.TaskAnalysisResult) TaskChange {
+	change := TaskChange{
+		UUID:        task.UUID,
+		Modified:    task.Modified,
+		Description: strings.TrimSpace(s.RefinedTask),
+		Project:     strings.TrimSpace(s.Project),
+		Priority:    normalizePriority(s.AdditionalInfo["priority"]),
+	}
+	for _, tag := range s.SuggestedTags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "+")
+		if tag != "" && !strings.ContainsAny(tag, " \t:") {
+			change.AddTags = append(change.AddTags, tag)
+		}
+	}
+	return change
+}
+
+// ChangeFromArgs builds a TaskChange for task from `task modify` arguments, split as by utils.SplitArgs.
+// ok is false if the arguments contain anything a TaskChange can not express, like due: or other
+// attributes and words with a colon. Those have to be passed to `task modify` itself.
+func ChangeFromArgs(task types.Task, args []string) (change TaskChange, ok bool) {
+	change = TaskChange{UUID: task.UUID, Modified: task.Modified}
+
+	var words []string
+	for _, arg := range args {
+		switch {
+		case len(arg) > 1 && strings.HasPrefix(arg, "+") && !strings.ContainsAny(arg, " :"):
+			change.AddTags = append(change.AddTags, arg[1:])
+
+		case len(arg) > 1 && strings.HasPrefix(arg, "-") && !strings.ContainsAny(arg, " :"):
+			change.RemoveTags = append(change.RemoveTags, arg[1:])
+
+		case strings.Contains(arg, " "):
+			// A quoted argument is description text
+			words = append(words, arg)
+
+		case strings.Contains(arg, ":"):
+			key, value, _ := strings.Cut(arg, ":")
+			key = strings.ToLower(key)
+			switch {
+			case len(key) >= 3 && strings.HasPrefix("project", key):
+				change.Project = value
+				change.ClearProject = value == ""
+			case len(key) >= 3 && strings.HasPrefix("priority", key):
+				change.Priority = normalizePriority(value)
+				change.ClearPriority = value == ""
+				if !strings.Contains("HML", change.Priority) {
+					return change, false
+				}
+			default:
+				return change, false
+			}
+
+		case strings.HasPrefix(arg, "/"):
+			// Substitutions like /old/new/
+			return change, false
+
+		default:
+			words = append(words, arg)
+		}
+	}
+	change.Description = strings.Join(words, " ")
+	return change, true
+}
+
+// normalizePriority turns priorities like "High" or "h" into TaskWarrior's H, M or L
+func normalizePriority(priority string) string {
+	priority = strings.ToUpper(strings.TrimSpace(priority))
+	switch {
+	case priority == "":
+		return ""
+	case strings.HasPrefix(priority, "H"):
+		return "H"
+	case strings.HasPrefix(priority, "M"):
+		return "M"
+	case strings.HasPrefix(priority, "L"):
+		return "L"
+	}
+	return priority
+}
+
+// IsEmpty reports whether the change would not modify anything
+func (ch TaskChange) IsEmpty() bool {
+	return ch.Description == "" && ch.Project == "" && ch.Priority == "" && !ch.ClearProject && !ch.ClearPriority &&
+		len(ch.AddTags) == 0 && len(ch.RemoveTags) == 0
+}
+
+// ApplyTo applies the change to task
+func (ch TaskChange) ApplyTo(task *types.Task) {
+	if ch.Description != "" {
+		task.Description = ch.Description
+	}
+	if ch.Project != "" || ch.ClearProject {
+		task.Project = ch.Project
+	}
+	if ch.Priority != "" || ch.ClearPriority {
+		task.Priority = ch.Priority
+	}
+
+	remove := make(map[string]bool)
+	for _, tag := range ch.RemoveTags {
+		remove[tag] = true
+	}
+
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range append(append([]string{}, task.Tags...), ch.AddTags...) {
+		if remove[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	task.Tags = tags
+}
+
+// ModifyTasks applies all changes with a single `task import` instead of one `task modify` per task.
+// Tasks that were modified since they were fetched are skipped and returned as conflicts.
+func (c *Client) ModifyTasks(changes []TaskChange) ([]Conflict, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	uuids := make([]string, 0, len(changes))
+	for _, change := range changes {
+		uuids = append(uuids, change.UUID)
+	}
+
+	current, err := c.GetTasksWithFilter(uuids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export tasks: %w", err)
+	}
+
+	byUUID := make(map[string]types.Task, len(current))
+	for _, task := range current {
+		byUUID[task.UUID] = task
+	}
+
+	now := types.TWTime(time.Now().UTC().Truncate(time.Second))
+	var conflicts []Conflict
+	var modified []types.Task
+
+	for _, change := range changes {
+		task, ok := byUUID[change.UUID]
+		if !ok {
+			return nil, fmt.Errorf("task %s not found", change.UUID)
+		}
+
+		if !task.Modified.Time().Equal(change.Modified.Time()) {
+			conflicts = append(conflicts, Conflict{Change: change, Current: task})
+			continue
+		}
+
+		change.ApplyTo(&task)
+		task.Modified = now
+		// Computed on export, not part of the stored task
+		task.ID = 0
+		task.Urgency = 0
+		modified = append(modified, task)
+	}
+
+	if len(modified) == 0 {
+		return conflicts, nil
+	}
+
+	if _, err := c.ImportTasks(modified); err != nil {
+		return conflicts, err
+	}
+
+	return conflicts, nil
+}
@@ -0,0 +1,86 @@
+package taskwarrior
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+	"github.com/taskvanguard/taskvanguard/pkg/utils"
+)
+
+func TestChangeFromSuggestionKeepsDescription(t *testing.T) {
+	task := types.Task{UUID: "u1", Description: "old", Project: "work"}
+	suggestion := types.TaskAnalysisResult{
+		RefinedTask:    "Reply re: invoice at 10:30 -urgent https://example.com/a",
+		Project:        "work.billing",
+		SuggestedTags:  []string{"+key", "fast", "+"},
+		AdditionalInfo: map[string]string{"priority": "High"},
+	}
+
+	change := ChangeFromSuggestion(task, suggestion)
+	if change.Description != suggestion.RefinedTask {
+		t.Errorf("description = %q, want %q", change.Description, suggestion.RefinedTask)
+	}
+	if change.Project != "work.billing" || change.Priority != "H" {
+		t.Errorf("project, priority = %q, %q", change.Project, change.Priority)
+	}
+	if !reflect.DeepEqual(change.AddTags, []string{"key", "fast"}) || len(change.RemoveTags) != 0 {
+		t.Errorf("tags = +%v -%v", change.AddTags, change.RemoveTags)
+	}
+}
+
+func TestChangeFromArgs(t *testing.T) {
+	task := types.Task{UUID: "u1", Project: "work", Priority: "M", Tags: []string{"old"}}
+
+	tests := []struct {
+		line string
+		ok   bool
+		want TaskChange
+	}{
+		{
+			line: `'Fix the -v flag' +cli -old project:tools pri:h`,
+			ok:   true,
+			want: TaskChange{Description: "Fix the -v flag", Project: "tools", Priority: "H", AddTags: []string{"cli"}, RemoveTags: []string{"old"}},
+		},
+		{
+			line: `Call Bob project: priority:`,
+			ok:   true,
+			want: TaskChange{Description: "Call Bob", ClearProject: true, ClearPriority: true},
+		},
+		{line: `Pay rent due:tomorrow`, ok: false},
+		{line: `Reply re: invoice`, ok: false},
+		{line: `/old/new/`, ok: false},
+		{line: `priority:urgent`, ok: false},
+	}
+
+	for _, test := range tests {
+		args, err := utils.SplitArgs(test.line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		change, ok := ChangeFromArgs(task, args)
+		if ok != test.ok {
+			t.Errorf("%s: ok = %v, want %v", test.line, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		test.want.UUID = task.UUID
+		if !reflect.DeepEqual(change, test.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", test.line, change, test.want)
+		}
+	}
+}
+
+func TestApplyToClearsFields(t *testing.T) {
+	task := types.Task{Description: "Call Bob", Project: "work", Priority: "M", Tags: []string{"a", "b"}}
+	TaskChange{ClearProject: true, ClearPriority: true, AddTags: []string{"c", "a"}, RemoveTags: []string{"b"}}.ApplyTo(&task)
+
+	if task.Description != "Call Bob" || task.Project != "" || task.Priority != "" {
+		t.Errorf("task = %+v, want project and priority cleared", task)
+	}
+	if !reflect.DeepEqual(task.Tags, []string{"a", "c"}) {
+		t.Errorf("tags = %v, want [a c]", task.Tags)
+	}
+}
@@ -3,6 +3,8 @@ package taskwarrior
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/taskvanguard/taskvanguard/internal/config"
@@ -25,6 +27,11 @@ func Bootstrap(cmd *cobra.Command) (*RuntimeContext, error) {
 		return nil, errors.New("TaskWarrior not found. Please install TaskWarrior first")
 	}
 
+	// Fail early on TaskWarrior versions whose output/import format is not understood
+	if _, err := client.Compat(); err != nil {
+		return nil, err
+	}
+
 	cfg, err := loadAndApplyFlagOverrides(cmd)
 	if err != nil {
 		return nil, err
@@ -35,8 +42,18 @@ func Bootstrap(cmd *cobra.Command) (*RuntimeContext, error) {
 		return nil, fmt.Errorf("failed to enrich config with Taskwarrior tags: %v", err)
 	}
 
-	if cfg.LLM.APIKey == "" {
-		return nil, errors.New("LLM API key not configured. Run 'taskvanguard init' first")
+	// Settings for this command. The key is resolved when the first prompt is sent,
+	// commands that never call the LLM do not run api_key_cmd.
+	command := CommandKey(cmd)
+	cfg.LLM = cfg.LLM.ForCommand(command)
+	if err := cfg.LLM.CheckModel(); err != nil {
+		return nil, err
+	}
+	// Commands calling the LLM fail here if no key is configured, before they change any task
+	if cfg.Settings.EnableLLM && slices.Contains(config.LLMCommands, command) {
+		if err := config.CheckAPIKey(cfg.LLM); err != nil {
+			return nil, err
+		}
 	}
 
 	goals, err := client.GetGoalsFiltered(cfg)
@@ -49,14 +66,6 @@ func Bootstrap(cmd *cobra.Command) (*RuntimeContext, error) {
 		return nil, err
 	}
 
-	// var tasks []types.Task
-	// if withTasks {
-	// 	tasks, err = client.GetTasksFiltered(cfg)
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	// }
-
 	return &RuntimeContext{
 		Config:       cfg,
 		Client:       client,
@@ -66,6 +75,11 @@ func Bootstrap(cmd *cobra.Command) (*RuntimeContext, error) {
 	}, nil
 }
 
+// CommandKey names a command in llm.commands: its path below the root command, e.g. "goals align"
+func CommandKey(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
 func loadAndApplyFlagOverrides(cmd *cobra.Command) (*types.Config, error) {
 	cfg, err := config.Load()
 	if err != nil {
@@ -80,6 +94,12 @@ func loadAndApplyFlagOverrides(cmd *cobra.Command) (*types.Config, error) {
 	if noTags, _ := cmd.Flags().GetBool("no-tags"); noTags {
 		cfg.Settings.EnableTagging = false
 	}
+	if model, _ := cmd.Flags().GetString("model"); model != "" {
+		cfg.LLM.CLI.Model = model
+	}
+	if provider, _ := cmd.Flags().GetString("provider"); provider != "" {
+		cfg.LLM.CLI.Provider = provider
+	}
 	return cfg, nil
 }
 
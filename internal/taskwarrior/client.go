@@ -1,26 +1,63 @@
 package taskwarrior
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/taskvanguard/taskvanguard/pkg/filter"
 	"github.com/taskvanguard/taskvanguard/pkg/types"
 )
 
-type Client struct{}
+type Client struct {
+	compat *Compat
+}
 
 func NewClient() *Client {
 	return &Client{}
 }
 
+// Compat detects the installed TaskWarrior version once and returns its compatibility settings
+func (c *Client) Compat() (*Compat, error) {
+	if c.compat != nil {
+		return c.compat, nil
+	}
+
+	raw, err := c.GetVersion()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine TaskWarrior version: %v", err)
+	}
+
+	version, err := ParseVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	compat, err := LookupCompat(version)
+	if err != nil {
+		return nil, err
+	}
+
+	c.compat = compat
+	return compat, nil
+}
+
+// createdTaskRe extracts the new task UUID from the output of `task add` with rc.verbose=new-uuid,
+// which every supported version prints as "Created task <uuid>." or "Created task <uuid> (recurrence template)."
+var createdTaskRe = regexp.MustCompile(`Created task ([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})`)
+
 func (c *Client) AddTaskToTaskWarrior(args []string) (string, int, error) {
-	cmdArgs := append([]string{"add"}, args...)
+	// Ask for the UUID instead of the ID, looking up the latest task could pick one added concurrently
+	cmdArgs := append([]string{"rc.verbose=new-uuid", "add"}, args...)
 	cmd := exec.Command("task", cmdArgs...)
 	
 	output, err := cmd.CombinedOutput()
@@ -30,21 +67,20 @@ func (c *Client) AddTaskToTaskWarrior(args []string) (string, int, error) {
 		return string(output), 0, errors.New(msg)
 	}
 
-	// Extract task ID from output using regex
-	// TaskWarrior typically outputs: "Created task 123."
-	re := regexp.MustCompile(`Created task (\d+)\.`)
-	matches := re.FindStringSubmatch(string(output))
+	matches := createdTaskRe.FindStringSubmatch(string(output))
 	if len(matches) < 2 {
-		msg := "could not extract task ID from output: " + string(output)
+		msg := "could not extract the created task from output: " + string(output)
 		return string(output), 0, errors.New(msg)
 	}
-	
-	taskID, err := strconv.Atoi(matches[1])
-	if err != nil {
-		msg := fmt.Sprintf("could not parse task ID: %v\nOutput: %s", err, string(output))
+
+	task, err := c.GetTaskByID(matches[1])
+	if err != nil || task == nil {
+		msg := fmt.Sprintf("could not look up created task %s: %v", matches[1], err)
 		return string(output), 0, errors.New(msg)
 	}
-	return string(output), taskID, nil
+
+	// Show the familiar ID instead of the UUID
+	return strings.Replace(string(output), matches[1], strconv.Itoa(task.ID), 1), task.ID, nil
 }
 
 func (c *Client) ModifyTaskInTaskWarrior(taskId int, args []string) (string, error) {
@@ -68,13 +104,61 @@ func (c *Client) AddSingleAnnotation(taskId string, value string) error {
 	
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		msg := "failed to add annotation to task " + taskId + ": " + err.Error() + "Output: " + string(output)
+		msg := "failed to add annotation to task " + taskId + ": " + err.Error() + "\nOutput: " + string(output)
 		return errors.New(msg)
 	}
 	
 	return nil
 }
 
+// ReplaceAnnotation swaps the annotation oldValue for newValue on a task. The task is rewritten
+// with a single import, so a failure leaves the old annotation in place instead of losing it.
+func (c *Client) ReplaceAnnotation(taskId string, oldValue string, newValue string) error {
+	if oldValue == newValue {
+		return nil
+	}
+
+	task, err := c.GetTaskByID(taskId)
+	if err != nil {
+		return fmt.Errorf("failed to read task %s: %w", taskId, err)
+	}
+	if task == nil {
+		return fmt.Errorf("task %s not found", taskId)
+	}
+	if !replaceAnnotation(task, oldValue, newValue) {
+		return fmt.Errorf("task %s has no annotation %q", taskId, oldValue)
+	}
+
+	if _, err := c.ImportTasks([]types.Task{*task}); err != nil {
+		return fmt.Errorf("failed to replace annotation of task %s: %w", taskId, err)
+	}
+	return nil
+}
+
+// replaceAnnotation changes the text of the first annotation matching oldValue, keeping its entry date
+func replaceAnnotation(task *types.Task, oldValue string, newValue string) bool {
+	for i, annotation := range task.Annotations {
+		if annotation.Description == oldValue {
+			annotations := slices.Clone(task.Annotations)
+			annotations[i].Description = newValue
+			task.Annotations = annotations
+			return true
+		}
+	}
+	return false
+}
+
+// UpsertAnnotation updates the first existing annotation starting with prefix in place
+// or adds value as a new annotation if there is none
+func (c *Client) UpsertAnnotation(taskId string, existing []types.Annotation, prefix string, value string) error {
+	for _, annotation := range existing {
+		if strings.HasPrefix(annotation.Description, prefix) {
+			return c.ReplaceAnnotation(taskId, annotation.Description, value)
+		}
+	}
+	return c.AddSingleAnnotation(taskId, value)
+}
+
 func (c *Client) GetTasks() ([]types.Task, error) {
 	cmd := exec.Command("task", "export")
 	output, err := cmd.Output()
@@ -105,24 +189,6 @@ func (c *Client) GetPendingTasks() ([]types.Task, error) {
 	return tasks, nil
 }
 
-// GetTasksFiltered returns all tasks with filtering applied
-func (c *Client) GetTasksFiltered(cfg *types.Config) ([]types.Task, error) {
-	tasks, err := c.GetTasks()
-	if err != nil {
-		return nil, err
-	}
-	return filter.FilterTasks(tasks, cfg), nil
-}
-
-// GetPendingTasksFiltered returns pending tasks with filtering applied
-func (c *Client) GetPendingTasksFiltered(cfg *types.Config) ([]types.Task, error) {
-	tasks, err := c.GetPendingTasks()
-	if err != nil {
-		return nil, err
-	}
-	return filter.FilterTasks(tasks, cfg), nil
-}
-
 func (c *Client) GetTaskByID(id string) (*types.Task, error) {
 	cmd := exec.Command("task", id, "export")
 	output, err := cmd.Output()
@@ -188,8 +254,9 @@ func (c *Client) GetTags() (map[string]int, error) {
 	return tagCounts, nil
 }
 
-func (c *Client) GetGoals() ([]types.Task, error) {
-	cmd := exec.Command("task", "project:goals", "status.not:deleted", "export")
+// GetGoals returns all goals that are not deleted, found the way settings.goal_mode configures
+func (c *Client) GetGoals(settings types.Settings) ([]types.Task, error) {
+	cmd := exec.Command("task", settings.GoalFilter(), "status.not:deleted", "export")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -205,7 +272,7 @@ func (c *Client) GetGoals() ([]types.Task, error) {
 
 // GetGoalsFiltered returns goal tasks with filtering applied
 func (c *Client) GetGoalsFiltered(cfg *types.Config) ([]types.Task, error) {
-	tasks, err := c.GetGoals()
+	tasks, err := c.GetGoals(cfg.Settings)
 	if err != nil {
 		return nil, err
 	}
@@ -213,7 +280,9 @@ func (c *Client) GetGoalsFiltered(cfg *types.Config) ([]types.Task, error) {
 }
 
 func (c *Client) GetProjects() ([]string, error) {
-	cmd := exec.Command("task", "projects")
+	// The `projects` report indents sub projects and only shows their last name segment,
+	// so the helper command is used which prints one full project name per line on 2.x and 3.x
+	cmd := exec.Command("task", "rc.verbose=nothing", "_projects")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -224,17 +293,10 @@ func (c *Client) GetProjects() ([]string, error) {
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		// Skip header lines and empty lines
-		if line == "" || strings.Contains(line, "Project") || strings.Contains(line, "----") {
+		if line == "" {
 			continue
 		}
-		
-		// Extract project name from the line (first column)
-		fields := strings.Fields(line)
-		if len(fields) > 0 {
-			project := fields[0]
-			projects = append(projects, project)
-		}
+		projects = append(projects, line)
 	}
 
 	return projects, nil
@@ -296,32 +358,82 @@ func (c *Client) GetTasksWithFilter(filterArgs []string) ([]types.Task, error) {
 	return tasks, nil
 }
 
-// GetTasksFiltered returns all tasks with filtering applied
-func (c *Client) GetTasksWithFilterFiltered(cfg *types.Config, filterArgs []string) ([]types.Task, error) {
-	tasks, err := c.GetTasksWithFilter(filterArgs)
+func (c *Client) StartTask(taskId string) error {
+	cmd := exec.Command("task", taskId, "start")
+	
+	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, err
+		msg := "failed to start task " + taskId + ": " + err.Error() + "\nOutput: " + string(output)
+		return errors.New(msg)
 	}
-	return filter.FilterTasks(tasks, cfg), nil
+	
+	return nil
 }
 
-// GetTasksFiltered returns all tasks with filtering applied
-func (c *Client) GetPendingTasksWithArgsFiltered(cfg *types.Config, filterArgs []string) ([]types.Task, error) {
-	tasks, err := c.GetPendingTasksWithArgs(filterArgs)
+// ImportTasks writes the given tasks to a temporary file, one JSON object per line,
+// and imports them with `task import`. Existing UUIDs are updated in place.
+func (c *Client) ImportTasks(tasks []types.Task) (string, error) {
+	compat, err := c.Compat()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	return filter.FilterTasks(tasks, cfg), nil
-}
 
-func (c *Client) StartTask(taskId string) error {
-	cmd := exec.Command("task", taskId, "start")
-	
+	var records []json.RawMessage
+	for _, task := range tasks {
+		record, err := marshalForImport(compat, task)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal task %s: %w", task.UUID, err)
+		}
+		records = append(records, record)
+	}
+
+	tempDir, err := os.MkdirTemp("", "taskvanguard-import-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tempFile := filepath.Join(tempDir, "tasks.json")
+	file, err := os.Create(tempFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	for _, record := range records {
+		if _, err := file.Write(append(record, '\n')); err != nil {
+			file.Close()
+			return "", fmt.Errorf("failed to write task to file: %w", err)
+		}
+	}
+	file.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "task", "import", tempFile)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		msg := "failed to start task " + taskId + ": " + err.Error() + "Output: " + string(output)
-		return errors.New(msg)
+		return string(output), fmt.Errorf("task import failed: %w\nOutput: %s", err, string(output))
 	}
-	
-	return nil
-}
\ No newline at end of file
+
+	return string(output), nil
+}
+
+// marshalForImport encodes a task in the format the installed TaskWarrior version expects
+func marshalForImport(compat *Compat, task types.Task) (json.RawMessage, error) {
+	data, err := json.Marshal(task)
+	if err != nil || compat.DependsArray || len(task.Depends) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	depends, err := json.Marshal(compat.DependsValue(task.Depends))
+	if err != nil {
+		return nil, err
+	}
+	fields["depends"] = depends
+	return json.Marshal(fields)
+}
@@ -0,0 +1,117 @@
+package taskwarrior
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// stubTask puts a fake task binary first in PATH that exports export.json, reports the task in it
+// as created on add and copies imports to imported.json. With failImport set the import fails. It returns the directory holding the files.
+func stubTask(t *testing.T, export string, failImport bool) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "export.json"), []byte(export), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	importCmd := `cp "$2" "` + dir + `/imported.json"`
+	if failImport {
+		importCmd = `echo "import refused"; exit 1`
+	}
+	script := `#!/bin/sh
+case "$1" in
+--version) echo 2.6.2 ;;
+import) ` + importCmd + ` ;;
+*) case "$*" in
+   *export*) cat "` + dir + `/export.json" ;;
+   "rc.verbose=new-uuid add "*) echo "Created task a1b2c3d4-0000-0000-0000-000000000000." ;;
+   *) echo "unexpected call: $*" >&2; exit 2 ;;
+   esac ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "task"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+const annotatedTask = `[{"id":4,"uuid":"a1b2c3d4-0000-0000-0000-000000000000","description":"write report","status":"pending",
+"entry":"20260101T100000Z","annotations":[{"entry":"20260102T100000Z","description":"🎯 Tip: old tip"},{"entry":"20260103T100000Z","description":"call back"}]}]`
+
+func TestReplaceAnnotationImportsOnce(t *testing.T) {
+	dir := stubTask(t, annotatedTask, false)
+
+	if err := NewClient().ReplaceAnnotation("4", "🎯 Tip: old tip", "🎯 Tip: new tip"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "imported.json"))
+	if err != nil {
+		t.Fatalf("nothing imported: %v", err)
+	}
+	var task types.Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		t.Fatal(err)
+	}
+	if len(task.Annotations) != 2 {
+		t.Fatalf("annotations = %+v", task.Annotations)
+	}
+	if task.Annotations[0].Description != "🎯 Tip: new tip" || task.Annotations[1].Description != "call back" {
+		t.Errorf("annotations = %+v", task.Annotations)
+	}
+	if got := task.Annotations[0].Entry.Time().Format("2006-01-02"); got != "2026-01-02" {
+		t.Errorf("entry of the replaced annotation changed to %s", got)
+	}
+}
+
+func TestReplaceAnnotationFailedImport(t *testing.T) {
+	stubTask(t, annotatedTask, true)
+
+	err := NewClient().ReplaceAnnotation("4", "🎯 Tip: old tip", "🎯 Tip: new tip")
+	if err == nil || !strings.Contains(err.Error(), "import refused") {
+		t.Errorf("want import error with output, got %v", err)
+	}
+}
+
+func TestReplaceAnnotationMissing(t *testing.T) {
+	dir := stubTask(t, annotatedTask, false)
+
+	if err := NewClient().ReplaceAnnotation("4", "no such note", "new"); err == nil {
+		t.Error("want error for a missing annotation")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "imported.json")); err == nil {
+		t.Error("task was imported although nothing was replaced")
+	}
+}
+
+func TestUpsertAnnotationAddsWithoutMatch(t *testing.T) {
+	dir := stubTask(t, annotatedTask, false)
+	// annotate is not handled by the stub, so an error proves the new annotation was added instead of replaced
+	existing := []types.Annotation{{Description: "call back"}}
+
+	err := NewClient().UpsertAnnotation("4", existing, "🎯 Tip:", "🎯 Tip: new tip")
+	if err == nil || !strings.Contains(err.Error(), "\nOutput: ") {
+		t.Errorf("want annotate error with separated output, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "imported.json")); err == nil {
+		t.Error("task was imported although no annotation matched")
+	}
+}
+
+func TestAddTaskLooksUpCreatedUUID(t *testing.T) {
+	stubTask(t, annotatedTask, false)
+
+	output, id, err := NewClient().AddTaskToTaskWarrior([]string{"write report"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 4 || output != "Created task 4.\n" {
+		t.Errorf("id = %d, output = %q", id, output)
+	}
+}
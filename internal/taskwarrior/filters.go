@@ -0,0 +1,90 @@
+package taskwarrior
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// PresetPrefix marks a filter argument as the name of a preset from config, e.g. @work
+const PresetPrefix = "@"
+
+// getRC reads a single TaskWarrior configuration value
+func (c *Client) getRC(name string) (string, error) {
+	output, err := exec.Command("task", "_get", "rc."+name).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ContextFilter returns the read filter of the active TaskWarrior context or "" if no context is set
+func (c *Client) ContextFilter() (string, error) {
+	name, err := c.getRC("context")
+	if err != nil {
+		return "", fmt.Errorf("read active context: %w", err)
+	}
+	if name == "" || name == "none" {
+		return "", nil
+	}
+
+	// 2.6+ has separate read and write filters, 2.5 only knows context.<name>
+	filter, err := c.getRC("context." + name + ".read")
+	if err != nil || filter == "" {
+		filter, err = c.getRC("context." + name)
+		if err != nil {
+			return "", fmt.Errorf("read filter of context %q: %w", name, err)
+		}
+	}
+	return filter, nil
+}
+
+// ResolveFilter expands @preset arguments and combines the result with the active TaskWarrior context
+// unless ignore_task_context is set
+func (c *Client) ResolveFilter(cfg *types.Config, args []string) ([]string, error) {
+	var resolved []string
+
+	if cfg.Settings.IgnoreTaskContext {
+		// Make sure TaskWarrior does not apply the context on its own either
+		resolved = append(resolved, "rc.context=")
+	} else {
+		contextFilter, err := c.ContextFilter()
+		if err != nil {
+			return nil, err
+		}
+		if contextFilter != "" {
+			resolved = append(resolved, "("+contextFilter+")")
+		}
+	}
+
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, PresetPrefix) {
+			resolved = append(resolved, arg)
+			continue
+		}
+
+		name := strings.TrimPrefix(arg, PresetPrefix)
+		preset, ok := cfg.Presets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter preset %q, available presets: %s", arg, strings.Join(PresetNames(cfg), ", "))
+		}
+		if strings.TrimSpace(preset) != "" {
+			resolved = append(resolved, "("+preset+")")
+		}
+	}
+
+	return resolved, nil
+}
+
+// PresetNames returns the configured preset names with their @ prefix, sorted
+func PresetNames(cfg *types.Config) []string {
+	names := make([]string, 0, len(cfg.Presets))
+	for name := range cfg.Presets {
+		names = append(names, PresetPrefix+name)
+	}
+	sort.Strings(names)
+	return names
+}
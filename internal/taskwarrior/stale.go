@@ -0,0 +1,85 @@
+package taskwarrior
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// StaleTask is a task that was modified after it was fetched
+type StaleTask struct {
+	Fetched types.Task
+	Current types.Task
+}
+
+// FindStale re-exports the given tasks and returns those whose modification time changed since they were fetched
+func (c *Client) FindStale(fetched []types.Task) ([]StaleTask, error) {
+	if len(fetched) == 0 {
+		return nil, nil
+	}
+
+	uuids := make([]string, 0, len(fetched))
+	for _, task := range fetched {
+		uuids = append(uuids, task.UUID)
+	}
+
+	current, err := c.GetTasksWithFilter(uuids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export tasks: %w", err)
+	}
+
+	byUUID := make(map[string]types.Task, len(current))
+	for _, task := range current {
+		byUUID[task.UUID] = task
+	}
+
+	var stale []StaleTask
+	for _, task := range fetched {
+		now, ok := byUUID[task.UUID]
+		if !ok {
+			return nil, fmt.Errorf("task %s no longer exists", task.UUID)
+		}
+		if !now.Modified.Time().Equal(task.Modified.Time()) {
+			stale = append(stale, StaleTask{Fetched: task, Current: now})
+		}
+	}
+
+	return stale, nil
+}
+
+// DescribeChanges lists the fields that differ between two versions of a task in a human readable form
+func DescribeChanges(before, after types.Task) []string {
+	var changes []string
+
+	field := func(name, old, new string) {
+		if old != new {
+			changes = append(changes, fmt.Sprintf("%s: %q → %q", name, old, new))
+		}
+	}
+
+	field("description", before.Description, after.Description)
+	field("status", before.Status, after.Status)
+	field("project", before.Project, after.Project)
+	field("priority", before.Priority, after.Priority)
+	field("tags", strings.Join(before.Tags, " "), strings.Join(after.Tags, " "))
+	field("depends", strings.Join(before.Depends, ","), strings.Join(after.Depends, ","))
+	field("goal", before.Goal, after.Goal)
+	field("due", formatOptionalTime(before.Due), formatOptionalTime(after.Due))
+	field("scheduled", formatOptionalTime(before.Scheduled), formatOptionalTime(after.Scheduled))
+	field("wait", formatOptionalTime(before.Wait), formatOptionalTime(after.Wait))
+	field("start", formatOptionalTime(before.Start), formatOptionalTime(after.Start))
+
+	if len(before.Annotations) != len(after.Annotations) {
+		changes = append(changes, fmt.Sprintf("annotations: %d → %d", len(before.Annotations), len(after.Annotations)))
+	}
+
+	return changes
+}
+
+func formatOptionalTime(t *types.TWTime) string {
+	if t == nil {
+		return ""
+	}
+	return t.Time().Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,123 @@
+package taskwarrior
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed TaskWarrior release number (e.g. 2.6.2, 3.1.0)
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+var versionRe = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion extracts the first dotted version number from the output of `task --version`
+func ParseVersion(raw string) (Version, error) {
+	matches := versionRe.FindStringSubmatch(raw)
+	if matches == nil {
+		return Version{}, fmt.Errorf("could not parse TaskWarrior version from %q", strings.TrimSpace(raw))
+	}
+
+	var v Version
+	v.Major, _ = strconv.Atoi(matches[1])
+	v.Minor, _ = strconv.Atoi(matches[2])
+	if matches[3] != "" {
+		v.Patch, _ = strconv.Atoi(matches[3])
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is an older release than other
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// Compat describes how TaskVanguard has to talk to one TaskWarrior release line
+type Compat struct {
+	Name    string
+	Version Version
+	// DependsArray is true if export/import represent depends as a JSON array instead of a comma separated string
+	DependsArray bool
+	// UniqueAnnotationEntries is true if annotations are keyed by their entry timestamp, so two
+	// annotations created within the same second overwrite each other on import
+	UniqueAnnotationEntries bool
+}
+
+type compatEntry struct {
+	min Version // inclusive
+	max Version // exclusive
+	Compat
+}
+
+// MinSupportedVersion is the oldest TaskWarrior release TaskVanguard works with
+var MinSupportedVersion = Version{2, 5, 0}
+
+// compatMatrix lists every supported TaskWarrior release line. Versions outside of it are rejected.
+var compatMatrix = []compatEntry{
+	{
+		min: Version{2, 5, 0},
+		max: Version{2, 6, 0},
+		Compat: Compat{
+			Name: "2.5",
+		},
+	},
+	{
+		min: Version{2, 6, 0},
+		max: Version{3, 0, 0},
+		Compat: Compat{
+			Name:         "2.6",
+			DependsArray: true,
+		},
+	},
+	{
+		min: Version{3, 0, 0},
+		max: Version{4, 0, 0},
+		Compat: Compat{
+			Name:                    "3.x",
+			DependsArray:            true,
+			UniqueAnnotationEntries: true,
+		},
+	},
+}
+
+// LookupCompat returns the compatibility settings for the given version or an actionable error if it is unsupported
+func LookupCompat(v Version) (*Compat, error) {
+	for _, entry := range compatMatrix {
+		if !v.Less(entry.min) && v.Less(entry.max) {
+			compat := entry.Compat
+			compat.Version = v
+			return &compat, nil
+		}
+	}
+
+	if v.Less(MinSupportedVersion) {
+		return nil, fmt.Errorf("TaskWarrior %s is not supported. Please upgrade to %s or newer (see https://taskwarrior.org/download/)", v, MinSupportedVersion)
+	}
+	return nil, fmt.Errorf("TaskWarrior %s is newer than any version TaskVanguard knows about. Please update TaskVanguard or install TaskWarrior 3.x", v)
+}
+
+// DependsValue returns the depends field in the representation the installed TaskWarrior expects on import
+func (c *Compat) DependsValue(uuids []string) interface{} {
+	if len(uuids) == 0 {
+		return nil
+	}
+	if c.DependsArray {
+		return uuids
+	}
+	return strings.Join(uuids, ",")
+}
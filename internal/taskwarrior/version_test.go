@@ -0,0 +1,78 @@
+package taskwarrior
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLookupCompat(t *testing.T) {
+	tests := []struct {
+		raw                     string
+		name                    string
+		dependsArray            bool
+		uniqueAnnotationEntries bool
+	}{
+		{"2.5.3", "2.5", false, false},
+		{"task 2.6.2 built for linux", "2.6", true, false},
+		{"3.0.0", "3.x", true, true},
+		{"3.4", "3.x", true, true},
+	}
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compat, err := LookupCompat(v)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.raw, err)
+		}
+		if compat.Name != tt.name || compat.DependsArray != tt.dependsArray || compat.UniqueAnnotationEntries != tt.uniqueAnnotationEntries {
+			t.Errorf("%s: got %+v", tt.raw, compat)
+		}
+		if compat.Version != v {
+			t.Errorf("%s: version = %s", tt.raw, compat.Version)
+		}
+	}
+}
+
+func TestLookupCompatUnsupported(t *testing.T) {
+	for raw, want := range map[string]string{"2.4.4": "upgrade", "4.0.0": "newer"} {
+		v, _ := ParseVersion(raw)
+		if _, err := LookupCompat(v); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: want error mentioning %q, got %v", raw, want, err)
+		}
+	}
+}
+
+func TestDependsValue(t *testing.T) {
+	uuids := []string{"a", "b"}
+
+	old, _ := LookupCompat(Version{2, 5, 1})
+	if got := old.DependsValue(uuids); got != "a,b" {
+		t.Errorf("2.5 depends = %v, want comma separated", got)
+	}
+
+	current, _ := LookupCompat(Version{3, 1, 0})
+	if got := current.DependsValue(uuids); !reflect.DeepEqual(got, uuids) {
+		t.Errorf("3.x depends = %v, want array", got)
+	}
+	if got := current.DependsValue(nil); got != nil {
+		t.Errorf("empty depends = %v", got)
+	}
+}
+
+func TestCreatedTaskRe(t *testing.T) {
+	uuid := "a1b2c3d4-0000-0000-0000-000000000000"
+	for _, output := range []string{
+		"Created task " + uuid + ".\n",
+		"Created task " + uuid + " (recurrence template).\n",
+	} {
+		if matches := createdTaskRe.FindStringSubmatch(output); len(matches) < 2 || matches[1] != uuid {
+			t.Errorf("%q: matches = %v", output, matches)
+		}
+	}
+	if createdTaskRe.MatchString("Created task 12.\n") {
+		t.Error("matched a task ID instead of a UUID")
+	}
+}
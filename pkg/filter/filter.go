@@ -23,17 +23,41 @@ func FilterTasks(tasks []types.Task, cfg *types.Config) []types.Task {
 
 // ShouldIncludeTask determines if a task should be included based on filter configuration
 func ShouldIncludeTask(task types.Task, cfg *types.Config) bool {
-	// Check project filtering
-	if !ShouldIncludeByProject(task.Project, cfg.Filters) {
-		return false
+	return ShouldInclude(task.Project, task.Tags, cfg.Filters)
+}
+
+// ShouldInclude combines project and tag filtering according to filter_combine.
+// Blacklists always exclude a task, "or" only lets a task pass either of the configured whitelists.
+func ShouldInclude(project string, tags []string, filters types.FiltersConfig) bool {
+	if strings.ToLower(filters.FilterCombine) != "or" {
+		return ShouldIncludeByProject(project, filters) && ShouldIncludeByTags(tags, filters)
 	}
 
-	// Check tag filtering
-	if !ShouldIncludeByTags(task.Tags, cfg.Filters) {
-		return false
+	rules := []struct {
+		configured bool
+		mode       string
+		passes     bool
+	}{
+		{len(filters.ProjectFilterProjects) > 0, filters.ProjectFilterMode, ShouldIncludeByProject(project, filters)},
+		{len(filters.TagFilterTags) > 0, filters.TagFilterMode, ShouldIncludeByTags(tags, filters)},
 	}
 
-	return true
+	hasWhitelist, whitelisted := false, false
+	for _, rule := range rules {
+		if !rule.configured {
+			continue
+		}
+		switch strings.ToLower(rule.mode) {
+		case "blacklist":
+			if !rule.passes {
+				return false
+			}
+		case "whitelist":
+			hasWhitelist = true
+			whitelisted = whitelisted || rule.passes
+		}
+	}
+	return !hasWhitelist || whitelisted
 }
 
 // ShouldIncludeByProject checks if task should be included based on project filters
@@ -42,11 +66,10 @@ func ShouldIncludeByProject(project string, filters types.FiltersConfig) bool {
 		return true // No project filter configured
 	}
 
-	projectLower := strings.ToLower(project)
 	containsProject := false
 
 	for _, filterProject := range filters.ProjectFilterProjects {
-		if strings.ToLower(filterProject) == projectLower {
+		if MatchProject(filterProject, project) {
 			containsProject = true
 			break
 		}
@@ -54,7 +77,7 @@ func ShouldIncludeByProject(project string, filters types.FiltersConfig) bool {
 
 	switch strings.ToLower(filters.ProjectFilterMode) {
 	case "blacklist":
-		return !containsProject // Exclude if project is in blacklist
+		return !containsProject && !hasInvalidPattern(filters.ProjectFilterProjects) // Exclude if project is in blacklist
 	case "whitelist":
 		return containsProject // Include only if project is in whitelist
 	default:
@@ -68,38 +91,22 @@ func ShouldIncludeByTags(tags []string, filters types.FiltersConfig) bool {
 		return true // No tag filter configured
 	}
 
-	// Convert all tags to lowercase for comparison
-	tagsLower := make([]string, len(tags))
-	for i, tag := range tags {
-		tagsLower[i] = strings.ToLower(tag)
-	}
+	containsTag := false
 
-	filterTagsLower := make([]string, len(filters.TagFilterTags))
-	for i, tag := range filters.TagFilterTags {
-		filterTagsLower[i] = strings.ToLower(tag)
+	for _, taskTag := range tags {
+		for _, filterTag := range filters.TagFilterTags {
+			if MatchTag(filterTag, taskTag) {
+				containsTag = true
+				break
+			}
+		}
 	}
 
 	switch strings.ToLower(filters.TagFilterMode) {
 	case "blacklist":
-		// Exclude if task has any blacklisted tag
-		for _, taskTag := range tagsLower {
-			for _, filterTag := range filterTagsLower {
-				if taskTag == filterTag {
-					return false
-				}
-			}
-		}
-		return true
+		return !containsTag && !hasInvalidPattern(filters.TagFilterTags) // Exclude if task has any blacklisted tag
 	case "whitelist":
-		// Include only if task has at least one whitelisted tag
-		for _, taskTag := range tagsLower {
-			for _, filterTag := range filterTagsLower {
-				if taskTag == filterTag {
-					return true
-				}
-			}
-		}
-		return false
+		return containsTag // Include only if task has at least one whitelisted tag
 	default:
 		return true // No filtering if mode is not recognized
 	}
@@ -141,11 +148,10 @@ func ShouldIncludeByTag(tag string, filters types.FiltersConfig) bool {
 		return true // No tag filter configured
 	}
 
-	tagLower := strings.ToLower(tag)
 	containsTag := false
 
 	for _, filterTag := range filters.TagFilterTags {
-		if strings.ToLower(filterTag) == tagLower {
+		if MatchTag(filterTag, tag) {
 			containsTag = true
 			break
 		}
@@ -153,11 +159,10 @@ func ShouldIncludeByTag(tag string, filters types.FiltersConfig) bool {
 
 	switch strings.ToLower(filters.TagFilterMode) {
 	case "blacklist":
-		return !containsTag // Exclude if tag is in blacklist
+		return !containsTag && !hasInvalidPattern(filters.TagFilterTags) // Exclude if tag is in blacklist
 	case "whitelist":
 		return containsTag // Include only if tag is in whitelist
 	default:
 		return true // No filtering if mode is not recognized
 	}
 }
-
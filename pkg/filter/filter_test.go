@@ -0,0 +1,80 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+func TestShouldInclude(t *testing.T) {
+	blacklists := types.FiltersConfig{
+		ProjectFilterMode:     "blacklist",
+		ProjectFilterProjects: []string{"pers.secret"},
+		TagFilterMode:         "blacklist",
+		TagFilterTags:         []string{"private"},
+	}
+	whitelists := types.FiltersConfig{
+		ProjectFilterMode:     "whitelist",
+		ProjectFilterProjects: []string{"work"},
+		TagFilterMode:         "whitelist",
+		TagFilterTags:         []string{"share"},
+	}
+	mixed := types.FiltersConfig{
+		ProjectFilterMode:     "whitelist",
+		ProjectFilterProjects: []string{"work"},
+		TagFilterMode:         "blacklist",
+		TagFilterTags:         []string{"private"},
+	}
+
+	tests := []struct {
+		name    string
+		filters types.FiltersConfig
+		combine string
+		project string
+		tags    []string
+		want    bool
+	}{
+		{"and: clean task", blacklists, "and", "work", []string{"x"}, true},
+		{"and: blacklisted project", blacklists, "and", "pers.secret.tax", nil, false},
+		{"or: blacklisted project without tags", blacklists, "or", "pers.secret", nil, false},
+		{"or: blacklisted tag", blacklists, "or", "work", []string{"Private"}, false},
+		{"or: clean task", blacklists, "or", "work", nil, true},
+		{"or: whitelisted project only", whitelists, "or", "work.api", nil, true},
+		{"or: whitelisted tag only", whitelists, "or", "home", []string{"share"}, true},
+		{"or: neither whitelist", whitelists, "or", "home", nil, false},
+		{"and: one whitelist only", whitelists, "and", "work", nil, false},
+		{"or: whitelisted but blacklisted tag", mixed, "or", "work", []string{"private"}, false},
+		{"or: whitelisted project", mixed, "or", "work", nil, true},
+		{"or: no rules", types.FiltersConfig{}, "or", "any", nil, true},
+	}
+
+	for _, test := range tests {
+		test.filters.FilterCombine = test.combine
+		if got := ShouldInclude(test.project, test.tags, test.filters); got != test.want {
+			t.Errorf("%s: ShouldInclude(%q, %v) = %v, want %v", test.name, test.project, test.tags, got, test.want)
+		}
+	}
+}
+
+func TestInvalidBlacklistPatternExcludes(t *testing.T) {
+	filters := types.FiltersConfig{
+		ProjectFilterMode:     "blacklist",
+		ProjectFilterProjects: []string{"/(secret/"},
+		TagFilterMode:         "blacklist",
+		TagFilterTags:         []string{"/[private/"},
+	}
+	if ShouldIncludeByProject("secret", filters) || ShouldIncludeByProject("work", filters) {
+		t.Error("project passed a blacklist with an invalid regex")
+	}
+	if ShouldIncludeByTags([]string{"private"}, filters) || ShouldIncludeByTags(nil, filters) {
+		t.Error("tags passed a blacklist with an invalid regex")
+	}
+	if ShouldIncludeByTag("private", filters) {
+		t.Error("tag passed a blacklist with an invalid regex")
+	}
+
+	filters.ProjectFilterMode = "whitelist"
+	if ShouldIncludeByProject("secret", filters) {
+		t.Error("invalid whitelist regex matched")
+	}
+}
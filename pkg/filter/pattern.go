@@ -0,0 +1,105 @@
+package filter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Filter patterns can be written as
+//   - plain names:  "work.secret" matches the project and all of its sub projects (work.secret.hr)
+//   - globs:        "pers.*", "*.secret", "client-?"
+//   - regexes:      "/^(pers|home)\./" enclosed in slashes like TaskWarrior description filters
+// All matching is case-insensitive.
+
+var (
+	regexCache   = make(map[string]*regexp.Regexp)
+	regexCacheMu sync.Mutex
+)
+
+func isRegexPattern(pattern string) bool {
+	return len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/")
+}
+
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCacheMu.Lock()
+	defer regexCacheMu.Unlock()
+
+	if re, ok := regexCache[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+	if err != nil {
+		return nil, err
+	}
+	regexCache[pattern] = re
+	return re, nil
+}
+
+// ValidatePattern reports whether a filter pattern can be used for matching
+func ValidatePattern(pattern string) error {
+	switch {
+	case isRegexPattern(pattern):
+		if _, err := compileRegex(pattern); err != nil {
+			return fmt.Errorf("invalid regex %s: %v", pattern, err)
+		}
+	case isGlobPattern(pattern):
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+			return fmt.Errorf("invalid glob %s: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// hasInvalidPattern reports whether any of the patterns can not be compiled. Validate rejects those in
+// the config, a blacklist still holding one excludes every task rather than letting hidden ones through.
+func hasInvalidPattern(patterns []string) bool {
+	for _, pattern := range patterns {
+		if ValidatePattern(pattern) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches regex and glob patterns. Plain names are compared literally.
+func matchPattern(pattern, value string) bool {
+	switch {
+	case isRegexPattern(pattern):
+		re, err := compileRegex(pattern)
+		if err != nil {
+			return false // invalid regexes never match, blacklists holding one exclude everything instead
+		}
+		return re.MatchString(value)
+	case isGlobPattern(pattern):
+		matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+		return matched
+	default:
+		return strings.EqualFold(pattern, value)
+	}
+}
+
+// MatchProject checks if a project matches a filter pattern. Plain names also match their sub projects.
+func MatchProject(pattern, project string) bool {
+	if project == "" {
+		return false
+	}
+	if matchPattern(pattern, project) {
+		return true
+	}
+	if isRegexPattern(pattern) || isGlobPattern(pattern) {
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(project), strings.ToLower(pattern)+".")
+}
+
+// MatchTag checks if a tag matches a filter pattern
+func MatchTag(pattern, tag string) bool {
+	return matchPattern(pattern, tag)
+}
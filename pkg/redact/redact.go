@@ -0,0 +1,154 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+// Detectors are the built-in rules that can be enabled via redaction.detectors
+var Detectors = map[string]*regexp.Regexp{
+	"url":   regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s"'<>]+`),
+	"email": regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`),
+	"iban":  regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`),
+	"phone": regexp.MustCompile(strings.Join(phoneFormats, "|")),
+}
+
+// phoneFormats are the phone number formats the phone detector matches. Each needs a country code,
+// an area code in parentheses or starting with 0, or the 3-3-4 grouping, so ranges like "pages 100-200" are kept.
+var phoneFormats = []string{
+	`\+\d{1,3}[ .-]?(?:\(\d{1,5}\)[ .-]?)?\d{2,5}(?:[ .-]?\d{2,5}){1,3}\b`, // +49 30 1234567, +1 (555) 123-4567
+	`\(\d{2,5}\)[ .-]?\d{3,4}[ .-]?\d{3,4}\b`,                              // (555) 123-4567
+	`\b0\d{2,4}[ /.-]\d{3,8}(?:[ .-]\d{2,4})?\b`,                           // 030 1234567, 0171/1234567
+	`\b\d{3}[.-]\d{3}[.-]\d{4}\b`,                                          // 555-123-4567
+}
+
+// detectorOrder makes sure URLs and emails are replaced before their parts can match other detectors
+var detectorOrder = []string{"url", "email", "iban", "phone"}
+
+type rule struct {
+	label   string
+	pattern *regexp.Regexp
+}
+
+// Redactor replaces sensitive text with placeholders like [EMAIL_1] and can map them back.
+// One Redactor should be used per command so placeholders stay stable across prompts.
+type Redactor struct {
+	rules        []rule
+	placeholders map[string]string // placeholder -> original
+	originals    map[string]string // original -> placeholder
+	counters     map[string]int
+}
+
+// New creates a Redactor from config. A disabled config yields a Redactor that changes nothing.
+func New(cfg types.RedactionConfig) (*Redactor, error) {
+	r := &Redactor{
+		placeholders: make(map[string]string),
+		originals:    make(map[string]string),
+		counters:     make(map[string]int),
+	}
+	if !cfg.Enabled {
+		return r, nil
+	}
+
+	// User rules first, they are usually more specific than the built-in detectors
+	for _, custom := range cfg.Rules {
+		re, err := regexp.Compile(custom.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction rule %q: %v", custom.Name, err)
+		}
+		label := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(custom.Name), " ", "_"))
+		if label == "" {
+			label = "REDACTED"
+		}
+		r.rules = append(r.rules, rule{label: label, pattern: re})
+	}
+
+	enabled := make(map[string]bool)
+	for _, name := range cfg.Detectors {
+		name = strings.ToLower(name)
+		if _, ok := Detectors[name]; !ok {
+			return nil, fmt.Errorf("unknown redaction detector %q", name)
+		}
+		enabled[name] = true
+	}
+	for _, name := range detectorOrder {
+		if enabled[name] {
+			r.rules = append(r.rules, rule{label: strings.ToUpper(name), pattern: Detectors[name]})
+		}
+	}
+
+	return r, nil
+}
+
+// Redact replaces every match of the configured rules with a placeholder
+func (r *Redactor) Redact(text string) string {
+	for _, rl := range r.rules {
+		text = rl.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			return r.placeholderFor(rl.label, match)
+		})
+	}
+	return text
+}
+
+func (r *Redactor) placeholderFor(label, original string) string {
+	if placeholder, ok := r.originals[original]; ok {
+		return placeholder
+	}
+	r.counters[label]++
+	placeholder := fmt.Sprintf("[%s_%d]", label, r.counters[label])
+	r.originals[original] = placeholder
+	r.placeholders[placeholder] = original
+	return placeholder
+}
+
+// RedactTask returns a copy of the task with description, annotations and text UDAs redacted
+func (r *Redactor) RedactTask(task types.Task) types.Task {
+	if len(r.rules) == 0 {
+		return task
+	}
+
+	task.Description = r.Redact(task.Description)
+
+	annotations := make([]types.Annotation, len(task.Annotations))
+	for i, annotation := range task.Annotations {
+		annotation.Description = r.Redact(annotation.Description)
+		annotations[i] = annotation
+	}
+	task.Annotations = annotations
+
+	if len(task.UDAs) > 0 {
+		udas := make(map[string]json.RawMessage, len(task.UDAs))
+		for name, value := range task.UDAs {
+			var text string
+			if err := json.Unmarshal(value, &text); err == nil {
+				value, _ = json.Marshal(r.Redact(text))
+			}
+			udas[name] = value
+		}
+		task.UDAs = udas
+	}
+
+	return task
+}
+
+// Restore maps placeholders in plain text back to the original values
+func (r *Redactor) Restore(text string) string {
+	for placeholder, original := range r.placeholders {
+		text = strings.ReplaceAll(text, placeholder, original)
+	}
+	return text
+}
+
+// RestoreJSON maps placeholders in a raw JSON response back to the original values,
+// escaping them so the response stays valid JSON
+func (r *Redactor) RestoreJSON(response string) string {
+	for placeholder, original := range r.placeholders {
+		escaped, _ := json.Marshal(original)
+		response = strings.ReplaceAll(response, placeholder, string(escaped[1:len(escaped)-1]))
+	}
+	return response
+}
@@ -0,0 +1,139 @@
+package redact
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/taskvanguard/taskvanguard/pkg/types"
+)
+
+func newRedactor(t *testing.T, detectors ...string) *Redactor {
+	t.Helper()
+	r, err := New(types.RedactionConfig{
+		Enabled:   true,
+		Detectors: detectors,
+		Rules:     []types.RedactionRule{{Name: "client", Pattern: `Acme Corp`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := newRedactor(t, "email", "url", "phone", "iban")
+	text := `Mail jane@example.com about https://example.com/x?a=1, call +49 30 1234567 and pay DE89 3704 0044 0532 0130 00 for Acme Corp`
+
+	redacted := r.Redact(text)
+	for _, secret := range []string{"jane@example.com", "example.com/x", "1234567", "DE89", "Acme Corp"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("redacted text still contains %q: %s", secret, redacted)
+		}
+	}
+	for _, placeholder := range []string{"[EMAIL_1]", "[URL_1]", "[PHONE_1]", "[IBAN_1]", "[CLIENT_1]"} {
+		if !strings.Contains(redacted, placeholder) {
+			t.Errorf("redacted text lacks %s: %s", placeholder, redacted)
+		}
+	}
+
+	if restored := r.Restore(redacted); restored != text {
+		t.Errorf("Restore() = %q, want %q", restored, text)
+	}
+}
+
+func TestPlaceholdersStable(t *testing.T) {
+	r := newRedactor(t, "email")
+
+	first := r.Redact("write to a@example.com")
+	second := r.Redact("a@example.com and b@example.com")
+	if first != "write to [EMAIL_1]" || second != "[EMAIL_1] and [EMAIL_2]" {
+		t.Errorf("placeholders not stable across calls: %q, %q", first, second)
+	}
+}
+
+func TestRestoreJSON(t *testing.T) {
+	r, err := New(types.RedactionConfig{Enabled: true, Rules: []types.RedactionRule{{Name: "secret", Pattern: `say "hi"`}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Redact(`say "hi"`)
+
+	var result map[string]string
+	if err := json.Unmarshal([]byte(r.RestoreJSON(`{"refined_task": "Do [SECRET_1] now"}`)), &result); err != nil {
+		t.Fatalf("restored JSON is invalid: %v", err)
+	}
+	if result["refined_task"] != `Do say "hi" now` {
+		t.Errorf("refined_task = %q", result["refined_task"])
+	}
+}
+
+func TestRedactTask(t *testing.T) {
+	r := newRedactor(t, "email")
+	task := types.Task{
+		Description: "ping a@example.com",
+		Annotations: []types.Annotation{{Description: "cc b@example.com"}},
+		UDAs:        map[string]json.RawMessage{"contact": json.RawMessage(`"a@example.com"`), "estimate": json.RawMessage(`3`)},
+	}
+
+	redacted := r.RedactTask(task)
+	if redacted.Description != "ping [EMAIL_1]" || redacted.Annotations[0].Description != "cc [EMAIL_2]" {
+		t.Errorf("task not redacted: %+v", redacted)
+	}
+	if string(redacted.UDAs["contact"]) != `"[EMAIL_1]"` || string(redacted.UDAs["estimate"]) != `3` {
+		t.Errorf("UDAs = %s, %s", redacted.UDAs["contact"], redacted.UDAs["estimate"])
+	}
+	if task.Description != "ping a@example.com" || task.Annotations[0].Description != "cc b@example.com" {
+		t.Errorf("original task was modified: %+v", task)
+	}
+}
+
+func TestPhone(t *testing.T) {
+	phones := []string{
+		"+49 30 1234567",
+		"+491711234567",
+		"+1 (555) 123-4567",
+		"+44 20 7946 0958",
+		"(555) 123-4567",
+		"030 1234567",
+		"0171/1234567",
+		"555-123-4567",
+		"555.123.4567",
+	}
+	for _, phone := range phones {
+		if got := Detectors["phone"].FindString("call " + phone + " today"); got != phone {
+			t.Errorf("phone %q matched as %q", phone, got)
+		}
+	}
+
+	notPhones := []string{
+		"read pages 100-200",
+		"budget 1500 2000",
+		"meeting 2026-10-19",
+		"release 1.23.4",
+		"from 09:00-17:00",
+		"order 12345 6789",
+		"room 101.202",
+	}
+	for _, text := range notPhones {
+		if got := Detectors["phone"].FindString(text); got != "" {
+			t.Errorf("%q matched phone %q", text, got)
+		}
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	r, err := New(types.RedactionConfig{Enabled: false, Detectors: []string{"email"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Redact("a@example.com"); got != "a@example.com" {
+		t.Errorf("disabled redactor changed text: %q", got)
+	}
+}
+
+func TestUnknownDetector(t *testing.T) {
+	if _, err := New(types.RedactionConfig{Enabled: true, Detectors: []string{"ssn"}}); err == nil {
+		t.Error("want error for unknown detector")
+	}
+}
@@ -1,11 +1,25 @@
 package types
 
 type Config struct {
+	ConfigVersion	int						`yaml:"config_version"`
 	LLM      	LLMConfig      				`yaml:"llm"`
 	Tags     	map[string]TagsMeta    		`yaml:"tags"`     // <== flattened here
 	Settings 	Settings	    			`yaml:"settings"`
 	Annotations map[string]AnnotationsMeta  `yaml:"annotations"`
 	Filters 	FiltersConfig			    `yaml:"filters"`
+	Redaction	RedactionConfig				`yaml:"redaction"`
+	Presets		map[string]string			`yaml:"presets"` // named filters, used as @name
+}
+
+type RedactionConfig struct {
+	Enabled		bool				`yaml:"enabled"`
+	Detectors	[]string			`yaml:"detectors"` // built-in: email, url, phone, iban
+	Rules		[]RedactionRule		`yaml:"rules"`
+}
+
+type RedactionRule struct {
+	Name	string	`yaml:"name"`    // used for the placeholder, e.g. client -> [CLIENT_1]
+	Pattern	string	`yaml:"pattern"` // regular expression
 }
 
 type FiltersConfig struct {
@@ -13,13 +27,37 @@ type FiltersConfig struct {
 	TagFilterTags 			[]string  	`yaml:"tag_filter_tags"`
 	ProjectFilterMode		string 		`yaml:"project_filter_mode"`
 	ProjectFilterProjects	[]string 	`yaml:"project_filter_projects"`
+	FilterCombine			string		`yaml:"filter_combine"` // "and" (default): tag and project rules must both pass, "or": one is enough
 }
 
 type LLMConfig struct {
-	Provider string `yaml:"provider"` // "openai" or "deepseek"
-	APIKey   string `yaml:"api_key"`
-	Model    string `yaml:"model"`
-	BaseURL  string `yaml:"base_url"`
+	Provider    string   `yaml:"provider"` // "openai", "deepseek" or "ollama"
+	APIKey      string   `yaml:"api_key"`
+	APIKeyEnv   string   `yaml:"api_key_env,omitempty"` // name of an environment variable holding the key
+	APIKeyCmd   string   `yaml:"api_key_cmd,omitempty"` // shell command printing the key, e.g. "pass show openai"
+	Model       string   `yaml:"model"`
+	BaseURL     string   `yaml:"base_url"`
+	Temperature *float64 `yaml:"temperature,omitempty"`
+	MaxTokens   int      `yaml:"max_tokens,omitempty"`
+
+	Commands  map[string]LLMOverride `yaml:"commands,omitempty"`  // keyed by command path, e.g. add, spot, goals align
+	Templates map[string]LLMOverride `yaml:"templates,omitempty"` // keyed by prompt template, e.g. guide_roadmap.md
+	CLI       LLMOverride            `yaml:"-"`                   // --provider/--model flags, always applied last
+
+	// BaseProvider is the provider of the base settings once overrides are applied, TASKVANGUARD_API_KEY belongs to it
+	BaseProvider string `yaml:"-"`
+}
+
+// LLMOverride replaces the LLM settings it sets and keeps the rest
+type LLMOverride struct {
+	Provider    string   `yaml:"provider,omitempty"`
+	APIKey      string   `yaml:"api_key,omitempty"`
+	APIKeyEnv   string   `yaml:"api_key_env,omitempty"`
+	APIKeyCmd   string   `yaml:"api_key_cmd,omitempty"`
+	Model       string   `yaml:"model,omitempty"`
+	BaseURL     string   `yaml:"base_url,omitempty"`
+	Temperature *float64 `yaml:"temperature,omitempty"`
+	MaxTokens   int      `yaml:"max_tokens,omitempty"`
 }
 
 type TagsMeta struct {
@@ -38,10 +76,21 @@ type Settings struct {
     EnableAnnotations  		bool   `yaml:"enable_annotations"`
 	EnableGoals 	   		bool   `yaml:"enable_goals"`
     GoalProjectName    		string `yaml:"goal_project_name"`
+	GoalMode				string `yaml:"goal_mode"` // "project" (default), "tag" or "uda", see GoalFilter
+	GoalTag					string `yaml:"goal_tag"`
+	GoalUDA					string `yaml:"goal_uda"`
+	GoalEstimateUDA			string `yaml:"goal_estimate_uda"` // numeric UDA weighting tasks in goal progress
+	GoalVelocityWeeks		int    `yaml:"goal_velocity_weeks"`
+	GoalReviewDays			int    `yaml:"goal_review_days"` // review interval of goals without a review UDA
+	GoalStaleDays			int    `yaml:"goal_stale_days"`
+	GoalHistoryInPrompts	bool   `yaml:"goal_history_in_prompts"` // tell the LLM which goals were achieved before
+	GoalHistoryLimit		int    `yaml:"goal_history_limit"`
+	ExtendedAnalysis		bool   `yaml:"extended_analysis"` // analyze single tasks with task_analysis_extended.md
 	TaskImportLimit 		int	   `yaml:"task_import_limit"`
     TaskProcessingBatchSize int	   `yaml:"task_processing_batch_size"`
     GuidingQuestionAmount   int    `yaml:"guiding_question_amount"`
     ContextTTLMinutes       int    `yaml:"context_ttl_minutes"`
+    IgnoreTaskContext       bool   `yaml:"ignore_task_context"`
 }
 
 type AnnotationsMeta struct {
@@ -49,12 +98,3 @@ type AnnotationsMeta struct {
 	Symbol string `yaml:"symbol"`
 	Desc string `yaml:"description"`	
 }
-
-// var ErrMissingLLMKey = errors.New("LLM API key not configured. Run 'taskvanguard init' first")
-
-// func (cfg *Config) Validate() error {
-// 	if cfg.LLM.APIKey == "" {
-// 		return ErrMissingLLMKey
-// 	}
-// 	return nil
-// }
\ No newline at end of file
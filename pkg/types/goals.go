@@ -0,0 +1,83 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Goal modes select how goals are told apart from other tasks
+const (
+	GoalModeProject = "project" // goals are tasks in goal_project_name or its sub projects
+	GoalModeTag     = "tag"     // goals carry the tag goal_tag
+	GoalModeUDA     = "uda"     // goals have the UDA goal_uda set to GoalUDAValue
+)
+
+// GoalUDAValue marks goals in uda mode, e.g. type:goal
+const GoalUDAValue = "goal"
+
+// GoalFilter returns the TaskWarrior filter matching all goals
+func (s Settings) GoalFilter() string {
+	switch s.GoalMode {
+	case GoalModeTag:
+		return "+" + s.GoalTag
+	case GoalModeUDA:
+		return s.GoalUDA + ":" + GoalUDAValue
+	default:
+		return "project:" + s.GoalProjectName
+	}
+}
+
+// GoalAddArgs returns the TaskWarrior arguments that turn a new task into a goal
+func (s Settings) GoalAddArgs() []string {
+	return []string{s.GoalFilter()}
+}
+
+// IsGoal reports whether a task is a goal
+func (s Settings) IsGoal(task Task) bool {
+	switch s.GoalMode {
+	case GoalModeTag:
+		for _, tag := range task.Tags {
+			if tag == s.GoalTag {
+				return true
+			}
+		}
+		return false
+	case GoalModeUDA:
+		var value string
+		if raw, ok := task.UDAs[s.GoalUDA]; ok && json.Unmarshal(raw, &value) == nil {
+			return value == GoalUDAValue
+		}
+		return false
+	default:
+		return task.Project == s.GoalProjectName || strings.HasPrefix(task.Project, s.GoalProjectName+".")
+	}
+}
+
+// GoalUUIDs returns the goals a task is linked to. Like depends, the goal UDA holds a comma separated list.
+func (t Task) GoalUUIDs() []string {
+	var uuids []string
+	for _, uuid := range strings.Split(t.Goal, ",") {
+		if uuid = strings.TrimSpace(uuid); uuid != "" {
+			uuids = append(uuids, uuid)
+		}
+	}
+	return uuids
+}
+
+// LinksGoal reports whether a task is linked to the goal with the given UUID
+func (t Task) LinksGoal(uuid string) bool {
+	for _, linked := range t.GoalUUIDs() {
+		if linked == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+// ParentGoal returns the parent of a goal, the first goal it is linked to
+func (t Task) ParentGoal() string {
+	if uuids := t.GoalUUIDs(); len(uuids) > 0 {
+		return uuids[0]
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package types
+
+import "fmt"
+
+// ForCommand returns the LLM settings for a command: the base settings overridden by
+// the command's block and finally by the CLI flags
+func (c LLMConfig) ForCommand(command string) LLMConfig {
+	resolved := c
+	if resolved.BaseProvider == "" {
+		resolved.BaseProvider = c.Provider
+	}
+	if override, ok := c.Commands[command]; ok {
+		override.applyTo(&resolved)
+	}
+	c.CLI.applyTo(&resolved)
+	return resolved
+}
+
+// ForTemplate returns the LLM settings for a prompt template. It is meant to be called on
+// settings already resolved for the command, the CLI flags still win over the template block.
+func (c LLMConfig) ForTemplate(template string) LLMConfig {
+	resolved := c
+	if override, ok := c.Templates[template]; ok {
+		override.applyTo(&resolved)
+	}
+	c.CLI.applyTo(&resolved)
+	return resolved
+}
+
+// CheckModel reports resolved settings without a model, which happens when the provider
+// was switched without naming a model for it
+func (c LLMConfig) CheckModel() error {
+	if c.Model != "" {
+		return nil
+	}
+	if c.CLI.Provider != "" && c.CLI.Model == "" {
+		return fmt.Errorf("--provider %s needs --model, the configured model belongs to another provider", c.CLI.Provider)
+	}
+	return fmt.Errorf("no model set for provider %s", c.Provider)
+}
+
+func (o LLMOverride) applyTo(c *LLMConfig) {
+	if o.Provider != "" && o.Provider != c.Provider {
+		c.Provider = o.Provider
+		// Base URL, key and model of another provider would send requests to the wrong API
+		c.BaseURL = ""
+		c.APIKey, c.APIKeyEnv, c.APIKeyCmd = "", "", ""
+		c.Model = ""
+	}
+	if o.APIKey != "" {
+		c.APIKey = o.APIKey
+	}
+	if o.APIKeyEnv != "" {
+		c.APIKeyEnv = o.APIKeyEnv
+	}
+	if o.APIKeyCmd != "" {
+		c.APIKeyCmd = o.APIKeyCmd
+	}
+	if o.Model != "" {
+		c.Model = o.Model
+	}
+	if o.BaseURL != "" {
+		c.BaseURL = o.BaseURL
+	}
+	if o.Temperature != nil {
+		c.Temperature = o.Temperature
+	}
+	if o.MaxTokens > 0 {
+		c.MaxTokens = o.MaxTokens
+	}
+}
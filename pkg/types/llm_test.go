@@ -0,0 +1,69 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func baseLLM() LLMConfig {
+	return LLMConfig{
+		Provider: "openai",
+		Model:    "gpt-4.1",
+		BaseURL:  "https://openrouter.ai/api/v1",
+		Commands: map[string]LLMOverride{
+			"goals align": {Provider: "deepseek", Model: "deepseek-chat"},
+			"spot":        {Model: "gpt-4.1-mini"},
+		},
+	}
+}
+
+func TestForCommandByPath(t *testing.T) {
+	resolved := baseLLM().ForCommand("goals align")
+	if resolved.Provider != "deepseek" || resolved.Model != "deepseek-chat" || resolved.BaseURL != "" {
+		t.Errorf("goals align resolved to %s/%s at %q", resolved.Provider, resolved.Model, resolved.BaseURL)
+	}
+	if resolved.BaseProvider != "openai" {
+		t.Errorf("BaseProvider = %q", resolved.BaseProvider)
+	}
+
+	if resolved := baseLLM().ForCommand("align"); resolved.Provider != "openai" || resolved.Model != "gpt-4.1" {
+		t.Errorf("align picked up the goals align override: %s/%s", resolved.Provider, resolved.Model)
+	}
+}
+
+func TestCLIProviderNeedsModel(t *testing.T) {
+	llm := baseLLM()
+	llm.CLI = LLMOverride{Provider: "ollama"}
+	resolved := llm.ForCommand("spot")
+	if resolved.Model != "" {
+		t.Errorf("model %q of another provider kept for ollama", resolved.Model)
+	}
+	err := resolved.CheckModel()
+	if err == nil || !strings.Contains(err.Error(), "--model") {
+		t.Errorf("want error asking for --model, got %v", err)
+	}
+
+	llm.CLI = LLMOverride{Provider: "ollama", Model: "llama3"}
+	resolved = llm.ForCommand("spot")
+	if resolved.Model != "llama3" || resolved.CheckModel() != nil {
+		t.Errorf("resolved model = %q", resolved.Model)
+	}
+}
+
+func TestCLISameProviderKeepsModel(t *testing.T) {
+	llm := baseLLM()
+	llm.CLI = LLMOverride{Provider: "openai"}
+	resolved := llm.ForCommand("spot")
+	if resolved.Model != "gpt-4.1-mini" || resolved.CheckModel() != nil {
+		t.Errorf("resolved model = %q", resolved.Model)
+	}
+}
+
+func TestForTemplateSwitchingProvider(t *testing.T) {
+	llm := baseLLM()
+	llm.Templates = map[string]LLMOverride{"spotlight.md": {Provider: "deepseek"}}
+	resolved := llm.ForCommand("spot").ForTemplate("spotlight.md")
+	if resolved.Provider != "deepseek" || resolved.CheckModel() == nil {
+		t.Errorf("resolved %s/%s without an error", resolved.Provider, resolved.Model)
+	}
+}
@@ -1,25 +1,132 @@
 package types
 
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
 type Annotation struct {
-	Entry       string `json:"entry"`
+	Entry       TWTime `json:"entry"`
 	Description string `json:"description"`
 }
 
+// Task mirrors the TaskWarrior export schema. Attributes that are not modelled
+// explicitly (user defined attributes) are kept in UDAs so they survive a re-import.
 type Task struct {
-	ID          int       `json:"id,omitempty"`
-	UUID        string    `json:"uuid"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	Priority    string    `json:"priority,omitempty"`
-	Project     string    `json:"project,omitempty"`
-	Tags        []string  `json:"tags,omitempty"`
-	Due         *string   `json:"due,omitempty"`
-	Entry       TWTime    `json:"entry"`
-	Modified    TWTime    `json:"modified"`
-	Urgency     float64   `json:"urgency,omitempty"`
-	Skipped		float64	  `json:"skipped"`
+	ID          int          `json:"id,omitempty"`
+	UUID        string       `json:"uuid"`
+	Description string       `json:"description"`
+	Status      string       `json:"status"`
+	Priority    string       `json:"priority,omitempty"`
+	Project     string       `json:"project,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Entry       TWTime       `json:"entry"`
+	Modified    TWTime       `json:"modified"`
+	Due         *TWTime      `json:"due,omitempty"`
+	Scheduled   *TWTime      `json:"scheduled,omitempty"`
+	Wait        *TWTime      `json:"wait,omitempty"`
+	Until       *TWTime      `json:"until,omitempty"`
+	Start       *TWTime      `json:"start,omitempty"`
+	End         *TWTime      `json:"end,omitempty"`
+	Recur       string       `json:"recur,omitempty"`
+	Mask        string       `json:"mask,omitempty"`
+	IMask       *int         `json:"imask,omitempty"`
+	Parent      string       `json:"parent,omitempty"`
+	Depends     UUIDList     `json:"depends,omitempty"`
+	Urgency     float64      `json:"urgency,omitempty"`
 	Annotations []Annotation `json:"annotations,omitempty"`
-	Goal        string    `json:"goal,omitempty"`
+	Goal        string       `json:"goal,omitempty"`
+	Skipped     float64      `json:"skipped,omitempty"`
+	Review      float64      `json:"review,omitempty"`   // goals: review interval in days
+	Reviewed    *TWTime      `json:"reviewed,omitempty"` // goals: last review
+
+	UDAs map[string]json.RawMessage `json:"-"`
+}
+
+// taskFields holds the JSON names of all explicitly modelled Task attributes
+var taskFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(Task{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// taskJSON has the same fields as Task but none of its methods, to avoid recursion when (un)marshalling
+type taskJSON Task
+
+func (t *Task) UnmarshalJSON(b []byte) error {
+	var plain taskJSON
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	for name, value := range raw {
+		if taskFields[name] {
+			continue
+		}
+		if plain.UDAs == nil {
+			plain.UDAs = make(map[string]json.RawMessage)
+		}
+		plain.UDAs[name] = value
+	}
+
+	*t = Task(plain)
+	return nil
+}
+
+func (t Task) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(taskJSON(t))
+	if err != nil || len(t.UDAs) == 0 {
+		return data, err
+	}
+
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for name, value := range t.UDAs {
+		if _, exists := merged[name]; !exists {
+			merged[name] = value
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// UUIDList is a list of task UUIDs. TaskWarrior 2.5 exports it as a comma
+// separated string, newer versions as a JSON array.
+type UUIDList []string
+
+func (l *UUIDList) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var joined string
+		if err := json.Unmarshal(b, &joined); err != nil {
+			return err
+		}
+		*l = nil
+		for _, uuid := range strings.Split(joined, ",") {
+			if uuid = strings.TrimSpace(uuid); uuid != "" {
+				*l = append(*l, uuid)
+			}
+		}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
 }
 
 // type Goal struct {
@@ -26,5 +26,5 @@ func (t TWTime) Time() time.Time {
 }
 
 func (t TWTime) MarshalJSON() ([]byte, error) {
-	return json.Marshal(time.Time(t).Format("20060102T150405Z"))
+	return json.Marshal(time.Time(t).UTC().Format("20060102T150405Z"))
 }
\ No newline at end of file
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTaskArgsKeepsDashWords(t *testing.T) {
+	parsed := ParseTaskArgs("Fix the -v flag +cli project:tools")
+	if parsed.Title != "Fix the -v flag" {
+		t.Errorf("title = %q", parsed.Title)
+	}
+	if parsed.Project != "tools" || !reflect.DeepEqual(parsed.Tags, []string{"cli"}) {
+		t.Errorf("parsed = %+v", parsed)
+	}
+}
+
+func TestSplitArgsRoundTrip(t *testing.T) {
+	args := []string{"task", "modify", "5", "Don't \"panic\" at $HOME", "+key", "project:a.b", "#1", ""}
+	var quoted []string
+	for _, arg := range args {
+		quoted = append(quoted, ShellQuote(arg))
+	}
+
+	line := ""
+	for i, arg := range quoted {
+		if i > 0 {
+			line += " "
+		}
+		line += arg
+	}
+
+	split, err := SplitArgs(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(split, args) {
+		t.Errorf("SplitArgs(%s) = %q, want %q", line, split, args)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	split, err := SplitArgs(`task modify 3 "Call \"Bob\"" Pay\ rent  'it''s'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"task", "modify", "3", `Call "Bob"`, "Pay rent", "its"}
+	if !reflect.DeepEqual(split, want) {
+		t.Errorf("got %q, want %q", split, want)
+	}
+
+	if _, err := SplitArgs(`task modify 3 "open`); err == nil {
+		t.Error("unterminated quote was accepted")
+	}
+}
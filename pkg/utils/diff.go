@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnifiedDiff returns the line differences between a and b in unified diff format with context lines around
+// each change, or "" if they are equal
+func UnifiedDiff(nameA, nameB, a, b string, context int) string {
+	if a == b {
+		return ""
+	}
+	linesA := splitLines(a)
+	linesB := splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of linesA[i:] and linesB[j:]
+	lcs := make([][]int, len(linesA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(linesB)+1)
+	}
+	for i := len(linesA) - 1; i >= 0; i-- {
+		for j := len(linesB) - 1; j >= 0; j-- {
+			if linesA[i] == linesB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type op struct {
+		kind byte // ' ', '-' or '+'
+		line string
+		a, b int // line numbers before the op
+	}
+	var ops []op
+	i, j := 0, 0
+	for i < len(linesA) || j < len(linesB) {
+		switch {
+		case i < len(linesA) && j < len(linesB) && linesA[i] == linesB[j]:
+			ops = append(ops, op{' ', linesA[i], i, j})
+			i++
+			j++
+		case i < len(linesA) && (j == len(linesB) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{'-', linesA[i], i, j})
+			i++
+		default:
+			ops = append(ops, op{'+', linesB[j], i, j})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		// Extend the hunk while changes are closer than two contexts apart
+		end := start
+		for k := start; k < len(ops); k++ {
+			if ops[k].kind != ' ' {
+				end = k
+			} else if k-end > 2*context {
+				break
+			}
+		}
+		from := max(0, start-context)
+		to := min(len(ops), end+context+1)
+
+		countA, countB := 0, 0
+		for _, o := range ops[from:to] {
+			if o.kind != '+' {
+				countA++
+			}
+			if o.kind != '-' {
+				countB++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", ops[from].a+1, countA, ops[from].b+1, countB)
+		for _, o := range ops[from:to] {
+			fmt.Fprintf(&out, "%c%s\n", o.kind, o.line)
+		}
+		start = to
+	}
+	return out.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}